@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/advisor.go
+
+package schema
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A contestant's score at some point in the match, e.g. before Final.
+type Standing struct {
+	ContestantID
+	Score Value
+}
+
+// Returns each contestant's score as of the end of the match so far.
+func (record *MatchRecord) Standings() []Standing {
+	timelines := record.ReplayScores()
+	standings := make([]Standing, len(timelines))
+	for i, timeline := range timelines {
+		standings[i].ContestantID = timeline.ContestantID
+		if len(timeline.Scores) > 0 {
+			standings[i].Score = timeline.Scores[len(timeline.Scores)-1]
+		}
+	}
+	return standings
+}
+
+type WagerRange struct {
+	Min, Max Wager
+}
+
+// Recommended Final wagers for a contestant.  A nil range means no wager can
+// achieve that strategy's goal.
+type WagerAdvice struct {
+	Standing
+	// Covers the bet: wins if correct, assuming the others wager to cover too.
+	// For a leader this covers the second place contestant doubling their score
+	// and for the others it passes the leader's score if they cover and are
+	// incorrect.  When the leader has a lock, it is the range which keeps it.
+	Cover *WagerRange
+	// Wins if correct, regardless of what anyone else wagers or responds.
+	Regardless  *WagerRange
+	Explanation string
+}
+
+// Advises each of the contestants on their Final wager, given their scores
+// before Final.  Advice is in the same order as the standings.
+func AdviseFinal(standings []Standing) []WagerAdvice {
+	advice := make([]WagerAdvice, len(standings))
+	for i, standing := range standings {
+		advice[i] = adviseFinal(standing, standings)
+	}
+	return advice
+}
+
+func adviseFinal(player Standing, standings []Standing) WagerAdvice {
+	advice := WagerAdvice{Standing: player}
+	score := player.Score
+	if score <= 0 {
+		advice.Explanation = fmt.Sprintf(
+			"%s cannot play Final with a score of %d.", player.Name, score)
+		return advice
+	}
+
+	// The other contestants who are playing Final, highest score first.
+	var others []Standing
+	for _, standing := range standings {
+		if standing.Key() != player.Key() && standing.Score > 0 {
+			others = append(others, standing)
+		}
+	}
+	if len(others) == 0 {
+		advice.Cover = &WagerRange{0, Wager(score)}
+		advice.Regardless = &WagerRange{0, Wager(score)}
+		advice.Explanation = fmt.Sprintf(
+			"%s is the only one playing Final and wins with any wager.", player.Name)
+		return advice
+	}
+	slices.SortStableFunc(others, func(a, b Standing) int {
+		return int(b.Score - a.Score)
+	})
+	rival := others[0]
+
+	if least := 2*rival.Score - score + 1; least <= score {
+		advice.Regardless = &WagerRange{Wager(max(least, 0)), Wager(score)}
+	}
+
+	switch {
+	case score > 2*rival.Score:
+		advice.Cover = &WagerRange{0, Wager(score - 2*rival.Score - 1)}
+		advice.Explanation = fmt.Sprintf(
+			"%s has a lock: wagering at most %d wins even if incorrect, since %s can reach at most %d.",
+			player.Name, advice.Cover.Max, rival.Name, 2*rival.Score)
+
+	case score > rival.Score:
+		advice.Cover = advice.Regardless
+		advice.Explanation = fmt.Sprintf(
+			"%s leads by %d: wagering at least %d covers %s doubling to %d, and wins if correct.",
+			player.Name, score-rival.Score, advice.Cover.Min, rival.Name, 2*rival.Score)
+
+	case score == rival.Score:
+		advice.Explanation = fmt.Sprintf(
+			"%s is tied with %s at %d: wagering everything wins if correct and %s is not.",
+			player.Name, rival.Name, score, rival.Name)
+
+	default:
+		// Trailing the leader, who is assumed to cover the second place score.
+		leader := rival
+		second := player
+		for _, standing := range others[1:] {
+			second.Score = max(second.Score, standing.Score)
+		}
+		if leader.Score > 2*second.Score {
+			advice.Explanation = fmt.Sprintf(
+				"%s cannot catch %s, who has a lock at %d; wager to protect second place.",
+				player.Name, leader.Name, leader.Score)
+			break
+		}
+		cover := max(2*second.Score-leader.Score+1, 0)
+		covered := leader.Score - cover
+		if least := covered - score + 1; least <= score {
+			advice.Cover = &WagerRange{Wager(max(least, 0)), Wager(score)}
+			advice.Explanation = fmt.Sprintf(
+				"If %s covers with %d and is incorrect, they finish with %d: wagering at least %d passes that if correct.",
+				leader.Name, cover, covered, advice.Cover.Min)
+		} else {
+			advice.Explanation = fmt.Sprintf(
+				"%s cannot pass the %d %s keeps by covering with %d and being incorrect; wager to protect second place.",
+				player.Name, covered, leader.Name, cover)
+		}
+	}
+	return advice
+}
+
+// The wager to suggest to the contestant (e.g. in the host's view of Final),
+// the least that covers the bet, with the explanation as its comments.  False
+// if there is no wager to suggest.
+func (advice WagerAdvice) Suggest(challenge ChallengeMetadata) (PlayerWager, bool) {
+	if advice.Cover == nil {
+		return PlayerWager{}, false
+	}
+	return PlayerWager{
+		ContestantID:      advice.ContestantID,
+		ChallengeMetadata: challenge,
+		Wager:             advice.Cover.Min,
+		Comments:          advice.Explanation,
+	}, true
+}
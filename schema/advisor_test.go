@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/advisor_test.go
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func standings(scores ...schema.Value) []schema.Standing {
+	names := []string{"Ada", "Bo", "Cy"}
+	standings := make([]schema.Standing, len(scores))
+	for i, score := range scores {
+		standings[i] = schema.Standing{
+			ContestantID: schema.ContestantID{PK: uint64(i + 1), Name: names[i]},
+			Score:        score}
+	}
+	return standings
+}
+
+func span(min, max schema.Wager) *schema.WagerRange {
+	return &schema.WagerRange{Min: min, Max: max}
+}
+
+func TestAdviseFinal(t *testing.T) {
+	tests := []struct {
+		name       string
+		scores     []schema.Value
+		cover      []*schema.WagerRange
+		regardless []*schema.WagerRange
+	}{
+		{"lock", []schema.Value{10000, 4000, 1000},
+			[]*schema.WagerRange{span(0, 1999), nil, nil},
+			[]*schema.WagerRange{span(0, 10000), nil, nil}},
+		{"close", []schema.Value{10000, 8000, 3000},
+			[]*schema.WagerRange{span(6001, 10000), span(0, 8000), span(1000, 3000)},
+			[]*schema.WagerRange{span(6001, 10000), nil, nil}},
+		{"tied", []schema.Value{5000, 5000},
+			[]*schema.WagerRange{nil, nil},
+			[]*schema.WagerRange{nil, nil}},
+		{"negative", []schema.Value{6000, -400, 2000},
+			[]*schema.WagerRange{span(0, 1999), nil, nil},
+			[]*schema.WagerRange{span(0, 6000), nil, nil}},
+		{"alone", []schema.Value{1200, 0},
+			[]*schema.WagerRange{span(0, 1200), nil},
+			[]*schema.WagerRange{span(0, 1200), nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			advice := schema.AdviseFinal(standings(tt.scores...))
+			if len(advice) != len(tt.scores) {
+				t.Fatalf("AdviseFinal() returned %d, want %d", len(advice), len(tt.scores))
+			}
+			for i, got := range advice {
+				if got.Explanation == "" {
+					t.Errorf("%s has no explanation", got.Name)
+				}
+				if !sameRange(got.Cover, tt.cover[i]) {
+					t.Errorf("%s cover = %v, want %v", got.Name, got.Cover, tt.cover[i])
+				}
+				if !sameRange(got.Regardless, tt.regardless[i]) {
+					t.Errorf("%s regardless = %v, want %v",
+						got.Name, got.Regardless, tt.regardless[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAdviseFinalByName(t *testing.T) {
+	advice := schema.AdviseFinal([]schema.Standing{
+		{ContestantID: schema.ContestantID{Name: "Ann"}, Score: 10000},
+		{ContestantID: schema.ContestantID{Name: "Bob"}, Score: 8000}})
+	if !sameRange(advice[0].Cover, span(6001, 10000)) {
+		t.Errorf("Ann cover = %v, want 6001-10000", advice[0].Cover)
+	}
+	if !sameRange(advice[1].Cover, span(0, 8000)) || advice[1].Regardless != nil {
+		t.Errorf("Bob cover, regardless = %v, %v; want 0-8000, nil",
+			advice[1].Cover, advice[1].Regardless)
+	}
+}
+
+func sameRange(a, b *schema.WagerRange) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestWagerAdviceSuggest(t *testing.T) {
+	challenge := schema.ChallengeMetadata{ChallengeID: 300}
+	advice := schema.AdviseFinal(standings(10000, 8000, 3000))
+
+	wager, ok := advice[0].Suggest(challenge)
+	if !ok {
+		t.Fatal("expected a suggestion for the leader")
+	}
+	if wager.PK != 1 || wager.ChallengeID != 300 || wager.Wager != 6001 {
+		t.Errorf("Suggest() = %+v", wager)
+	}
+	if wager.Comments != advice[0].Explanation {
+		t.Errorf("Suggest() comments = %q, want the explanation", wager.Comments)
+	}
+	if err := (schema.ClassicWagers{}).Validate(wager, schema.ScoreSnapshot{
+		Round:  schema.ROUND_FINAL,
+		Scores: map[schema.ContestantKey]schema.Value{{PK: 1}: 10000, {PK: 2}: 8000, {PK: 3}: 3000},
+	}); err != nil {
+		t.Errorf("suggested wager is not valid: %v", err)
+	}
+
+	locked := schema.AdviseFinal(standings(10000, 4000, 1000))
+	if _, ok := locked[1].Suggest(challenge); ok {
+		t.Error("expected no suggestion when the leader has a lock")
+	}
+}
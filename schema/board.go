@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/board.go
+
+package schema
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// A column of the layout is a single byte, so boards have at most this many rows.
+const MaxBoardRows = 8
+
+// The rows of a standard board, for boards whose round has no rules (such as
+// those assembled by the host, see RoundRules).
+const DefaultBoardRows = 5
+
+var (
+	ErrBoardSize       = errors.New("unsupported board size")
+	ErrBoardLayout     = errors.New("malformed board layout")
+	ErrPositionBounds  = errors.New("position is not on the board")
+	ErrPositionMissing = errors.New("position is missing from the board")
+	ErrPositionTaken   = errors.New("position was already selected")
+	ErrNotInteractive  = errors.New("round is not played interactively")
+)
+
+func (position BoardPosition) String() string {
+	return fmt.Sprintf("column %d, index %d", position.Column, position.Index)
+}
+
+// Begins a board where all positions (except those missing) are available.  The
+// number of rows is that of the board's round, see Board.Rows.
+func NewBoardState(board Board) (*BoardState, error) {
+	rows := board.Rows()
+	if rows == 0 || rows > MaxBoardRows || len(board.Columns) == 0 {
+		return nil, fmt.Errorf("%w: %d columns of %d rows",
+			ErrBoardSize, len(board.Columns), rows)
+	}
+	state := &BoardState{
+		Board:  board,
+		Layout: NewBoardLayout(uint(len(board.Columns)), rows),
+	}
+	for _, position := range board.Missing {
+		if state.Layout.Has(position) {
+			state.Layout.Clear(position)
+		}
+	}
+	return state, nil
+}
+
+// Selects the position, making it unavailable for the rest of the round.
+func (state *BoardState) Select(selection BoardSelection) error {
+	if !state.Round.Rules().Interactive {
+		return fmt.Errorf("%w: %s", ErrNotInteractive, state.RoundName())
+	}
+	position := selection.BoardPosition
+	if position.Column == 0 || position.Column > uint(len(state.Layout)) ||
+		position.Index == 0 || position.Index > state.Rows() {
+		return fmt.Errorf("%w: %s", ErrPositionBounds, position)
+	}
+	for _, missing := range state.Missing {
+		if missing == position {
+			return fmt.Errorf("%w: %s", ErrPositionMissing, position)
+		}
+	}
+	if !state.Layout.Has(position) {
+		return fmt.Errorf("%w: %s", ErrPositionTaken, position)
+	}
+	state.Layout.Clear(position)
+	return nil
+}
+
+// The number of rows on the board, as given by the rules of its round.  When
+// the round has no rules the board has the default number of rows, or as many
+// as are needed to reach its missing positions.
+func (board Board) Rows() uint {
+	if rows := board.Round.Rules().Rows; rows > 0 {
+		return rows
+	}
+	rows := uint(DefaultBoardRows)
+	for _, position := range board.Missing {
+		rows = max(rows, position.Index)
+	}
+	return rows
+}
+
+// Adds the outcome to the history, selecting its position if it is available.
+// Further outcomes for the most recent selection are the other contestants'
+// responses to it, any other outcome must be for an available position.
+func (state *BoardState) Apply(outcome SelectionOutcome) error {
+	count := len(state.History)
+	if count == 0 || state.History[count-1].BoardPosition != outcome.BoardPosition {
+		if err := state.Select(outcome.BoardSelection); err != nil {
+			return err
+		}
+	}
+	state.History = append(state.History, outcome)
+	return nil
+}
+
+// The positions which are still available, ordered by column and then index.
+func (state *BoardState) Remaining() []BoardPosition {
+	return state.Layout.Positions()
+}
+
+// True when there are no more positions available.
+func (state *BoardState) IsCleared() bool {
+	for _, column := range state.Layout {
+		if column != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// The layout has one byte for each column (category) of the board, in order,
+// where bit (index-1) is set if the position at that index is still available.
+// The least significant bit is the top of the column.  For example, a column
+// where only the top and bottom of five positions remain is 0b10001 (0x11).
+//
+// In JSON this is encoded as base64, as all byte slices are.  Its String() is
+// a more readable hexadecimal form, two digits per column, which is read by
+// ParseBoardLayout; the same column above would be "11".
+func NewBoardLayout(columns, rows uint) BoardLayout {
+	layout := make(BoardLayout, columns)
+	for i := range layout {
+		layout[i] = byte(1<<rows - 1)
+	}
+	return layout
+}
+
+func ParseBoardLayout(encoded string) (BoardLayout, error) {
+	layout, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrBoardLayout, encoded)
+	}
+	return BoardLayout(layout), nil
+}
+
+func (layout BoardLayout) String() string {
+	return hex.EncodeToString(layout)
+}
+
+// True if the position is on the board and still available.  The layout does
+// not record the number of rows, but the bits past the last row are never set
+// (see NewBoardLayout) so those positions are not available.
+func (layout BoardLayout) Has(position BoardPosition) bool {
+	if position.Column == 0 || position.Column > uint(len(layout)) ||
+		position.Index == 0 || position.Index > MaxBoardRows {
+		return false
+	}
+	return layout[position.Column-1]&(1<<(position.Index-1)) != 0
+}
+
+// Marks the position as unavailable, the position must be on the board.
+func (layout BoardLayout) Clear(position BoardPosition) {
+	layout[position.Column-1] &^= 1 << (position.Index - 1)
+}
+
+// The available positions, ordered by column and then index.
+func (layout BoardLayout) Positions() []BoardPosition {
+	var positions []BoardPosition
+	for column, bits := range layout {
+		for index := uint(1); bits != 0; index, bits = index+1, bits>>1 {
+			if bits&1 != 0 {
+				positions = append(positions,
+					BoardPosition{Column: uint(column + 1), Index: index})
+			}
+		}
+	}
+	return positions
+}
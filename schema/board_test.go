@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/board_test.go
+
+package schema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func selection(column, index uint) schema.BoardSelection {
+	return schema.BoardSelection{
+		BoardPosition: schema.BoardPosition{Column: column, Index: index}}
+}
+
+func TestBoardStateSelect(t *testing.T) {
+	board := schema.Board{
+		RoundID: schema.RoundID{Round: schema.ROUND_SINGLE},
+		Columns: make([]schema.CategoryMetadata, 2),
+		Missing: []schema.BoardPosition{{Column: 2, Index: 3}}}
+	state, err := schema.NewBoardState(board)
+	if err != nil {
+		t.Fatalf("NewBoardState() error = %v", err)
+	}
+	if state.Layout.String() != "1f1b" {
+		t.Errorf("initial layout = %s, expected 1f1b", state.Layout)
+	}
+
+	tests := []struct {
+		name      string
+		selection schema.BoardSelection
+		err       error
+	}{
+		{"available", selection(1, 1), nil},
+		{"taken", selection(1, 1), schema.ErrPositionTaken},
+		{"missing", selection(2, 3), schema.ErrPositionMissing},
+		{"no column", selection(3, 1), schema.ErrPositionBounds},
+		{"zero index", selection(1, 0), schema.ErrPositionBounds},
+		{"past the last row", selection(1, 6), schema.ErrPositionBounds},
+		{"past the layout", selection(1, 9), schema.ErrPositionBounds},
+		{"another", selection(2, 2), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := state.Select(tt.selection); !errors.Is(err, tt.err) {
+				t.Errorf("Select(%s) error = %v, expected %v", tt.selection.BoardPosition, err, tt.err)
+			}
+		})
+	}
+
+	remaining := []schema.BoardPosition{
+		{Column: 1, Index: 2}, {Column: 1, Index: 3}, {Column: 1, Index: 4}, {Column: 1, Index: 5},
+		{Column: 2, Index: 1}, {Column: 2, Index: 4}, {Column: 2, Index: 5}}
+	if !reflect.DeepEqual(state.Remaining(), remaining) {
+		t.Errorf("Remaining() = %v, expected %v", state.Remaining(), remaining)
+	}
+	for _, position := range remaining {
+		if state.IsCleared() {
+			t.Fatal("cleared with positions remaining")
+		}
+		state.Select(schema.BoardSelection{BoardPosition: position})
+	}
+	if !state.IsCleared() || len(state.Remaining()) != 0 {
+		t.Errorf("not cleared, layout = %s", state.Layout)
+	}
+}
+
+func TestBoardStateApply(t *testing.T) {
+	state, _ := schema.NewBoardState(schema.Board{
+		RoundID: schema.RoundID{Round: schema.ROUND_DOUBLE},
+		Columns: make([]schema.CategoryMetadata, 6)})
+	outcomes := []struct {
+		outcome schema.SelectionOutcome
+		err     error
+	}{
+		{outcome(1, 1, false), nil},
+		{outcome(1, 1, true), nil}, // responding to the same selection
+		{outcome(6, 5, true), nil},
+		{outcome(1, 1, true), schema.ErrPositionTaken},
+	}
+	for _, tt := range outcomes {
+		if err := state.Apply(tt.outcome); !errors.Is(err, tt.err) {
+			t.Errorf("Apply(%s) error = %v, expected %v", tt.outcome.BoardPosition, err, tt.err)
+		}
+	}
+	if len(state.History) != 3 {
+		t.Errorf("history has %d outcomes, expected 3", len(state.History))
+	}
+	if state.Layout.String() != "1e1f1f1f1f0f" {
+		t.Errorf("layout = %s", state.Layout)
+	}
+}
+
+func TestBoardLayout(t *testing.T) {
+	if state, err := schema.NewBoardState(schema.Board{Columns: make([]schema.CategoryMetadata, 6)}); err != nil || state.Layout.String() != "1f1f1f1f1f1f" {
+		t.Errorf("unknown round = %v, %v", state, err)
+	}
+	if _, err := schema.NewBoardState(schema.Board{RoundID: schema.RoundID{Round: schema.ROUND_SINGLE}}); !errors.Is(err, schema.ErrBoardSize) {
+		t.Errorf("no columns error = %v", err)
+	}
+	if _, err := schema.ParseBoardLayout("1f1"); !errors.Is(err, schema.ErrBoardLayout) {
+		t.Errorf("odd length error = %v", err)
+	}
+	layout, err := schema.ParseBoardLayout("11001f")
+	if err != nil {
+		t.Fatalf("ParseBoardLayout() error = %v", err)
+	}
+	positions := []schema.BoardPosition{
+		{Column: 1, Index: 1}, {Column: 1, Index: 5},
+		{Column: 3, Index: 1}, {Column: 3, Index: 2}, {Column: 3, Index: 3},
+		{Column: 3, Index: 4}, {Column: 3, Index: 5}}
+	if !reflect.DeepEqual(layout.Positions(), positions) {
+		t.Errorf("Positions() = %v", layout.Positions())
+	}
+}
+
+func TestRoundRecordReplay(t *testing.T) {
+	round := testRecord().Rounds[0]
+	state, err := round.Replay()
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	remaining := []schema.BoardPosition{{Column: 1, Index: 4}, {Column: 1, Index: 5}}
+	if !reflect.DeepEqual(state.Remaining(), remaining) {
+		t.Errorf("Remaining() after Replay() = %v, expected %v", state.Remaining(), remaining)
+	}
+}
+
+func TestBoardStateRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		round   schema.RoundEnum
+		missing []schema.BoardPosition
+		rows    uint
+	}{
+		{"single", schema.ROUND_SINGLE, nil, 5},
+		{"final", schema.ROUND_FINAL, nil, 1},
+		{"tiebreaker", schema.ROUND_TIEBREAKER, nil, 1},
+		{"unaired", schema.ROUND_UNKNOWN, nil, 5},
+		{"unaired, missing row 7", schema.ROUND_UNKNOWN,
+			[]schema.BoardPosition{{Column: 2, Index: 7}}, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := schema.NewBoardState(schema.Board{
+				RoundID: schema.RoundID{Round: tt.round},
+				Columns: make([]schema.CategoryMetadata, 2),
+				Missing: tt.missing})
+			if err != nil {
+				t.Fatalf("NewBoardState() error = %v", err)
+			}
+			if state.Rows() != tt.rows {
+				t.Errorf("Rows() = %d, expected %d", state.Rows(), tt.rows)
+			}
+			if err := state.Select(selection(1, tt.rows+1)); !errors.Is(err, schema.ErrPositionBounds) {
+				t.Errorf("Select(1, %d) error = %v", tt.rows+1, err)
+			}
+			if err := state.Select(selection(1, tt.rows)); err != nil {
+				t.Errorf("Select(1, %d) error = %v", tt.rows, err)
+			}
+		})
+	}
+
+	_, err := schema.NewBoardState(schema.Board{
+		Columns: make([]schema.CategoryMetadata, 1),
+		Missing: []schema.BoardPosition{{Column: 1, Index: schema.MaxBoardRows + 1}}})
+	if !errors.Is(err, schema.ErrBoardSize) {
+		t.Errorf("NewBoardState() of too many rows error = %v", err)
+	}
+}
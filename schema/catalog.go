@@ -0,0 +1,291 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/catalog.go
+
+package schema
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// A MatchIndex along with the secondary indexes needed to query it by date,
+// season and contestant without scanning the whole archive.  The indexes are
+// kept current by Update and Merge, so the matches should not be modified
+// directly while the catalog is in use.
+//
+// The indexes are kept here rather than on MatchIndex because MatchIndex is the
+// map that is (un)marshaled as a season's episodes, described by the CUE and
+// Zod schemas; it has no room for unexported state and a map cannot tell when
+// its entries are assigned to directly.  MatchIndex has the same queries, each
+// building a catalog first.
+type MatchCatalog struct {
+	matches MatchIndex
+
+	// Each of these is kept in order of airedBefore, except numbers.
+	numbers     []*MatchMetadata
+	aired       []*MatchMetadata
+	seasons     map[SeasonSlug][]*MatchMetadata
+	contestants map[ContestantKey][]*MatchMetadata
+}
+
+// Builds the catalog's indexes over matches, which the catalog then owns.  A nil
+// index begins an empty catalog.
+func NewMatchCatalog(matches MatchIndex) *MatchCatalog {
+	if matches == nil {
+		matches = make(MatchIndex)
+	}
+	catalog := &MatchCatalog{
+		matches:     matches,
+		numbers:     make([]*MatchMetadata, 0, len(matches)),
+		aired:       make([]*MatchMetadata, 0, len(matches)),
+		seasons:     make(map[SeasonSlug][]*MatchMetadata),
+		contestants: make(map[ContestantKey][]*MatchMetadata),
+	}
+	for _, episode := range matches {
+		catalog.numbers = append(catalog.numbers, episode)
+		catalog.aired = append(catalog.aired, episode)
+		if episode.SeasonSlug != "" {
+			catalog.seasons[episode.SeasonSlug] = append(
+				catalog.seasons[episode.SeasonSlug], episode)
+		}
+		for _, key := range contestantKeys(episode) {
+			catalog.contestants[key] = append(catalog.contestants[key], episode)
+		}
+	}
+
+	slices.SortFunc(catalog.numbers, func(a, b *MatchMetadata) int {
+		return cmp.Compare(a.MatchNumber, b.MatchNumber)
+	})
+	sortAired(catalog.aired)
+	for _, episodes := range catalog.seasons {
+		sortAired(episodes)
+	}
+	for _, episodes := range catalog.contestants {
+		sortAired(episodes)
+	}
+	return catalog
+}
+
+// The underlying matches, e.g. for writing them.
+func (catalog *MatchCatalog) Matches() MatchIndex {
+	return catalog.matches
+}
+
+func (catalog *MatchCatalog) Len() int {
+	return len(catalog.matches)
+}
+
+// Same as MatchIndex.Update, also updating the catalog's indexes.
+func (catalog *MatchCatalog) Update(metadata MatchMetadata) []MergeConflict {
+	return catalog.Merge(metadata, DefaultMergePolicy)
+}
+
+// Same as MatchIndex.Merge, also updating the catalog's indexes.
+func (catalog *MatchCatalog) Merge(metadata MatchMetadata, policy MergePolicy) []MergeConflict {
+	episode, exists := catalog.matches[metadata.MatchNumber]
+	if exists {
+		catalog.unindex(episode)
+	}
+	conflicts := catalog.matches.Merge(metadata, policy)
+	if !exists {
+		episode = catalog.matches[metadata.MatchNumber]
+		catalog.numbers = insertSorted(catalog.numbers, episode, numberedBefore)
+	}
+	catalog.index(episode)
+	return conflicts
+}
+
+// The order of matches returned by MatchCatalog.Ordered.
+type MatchOrderEnum uint8
+
+const (
+	ORDER_MATCH_NUMBER MatchOrderEnum = iota
+	ORDER_AIRED_DATE                  // matches without an aired date are last
+)
+
+// Iterates over all matches in the requested order, without copying the index.
+// The catalog should not be updated during the iteration.
+func (catalog *MatchCatalog) Ordered(order MatchOrderEnum) iter.Seq[*MatchMetadata] {
+	if order == ORDER_AIRED_DATE {
+		return slices.Values(catalog.aired)
+	}
+	return slices.Values(catalog.numbers)
+}
+
+// Returns the matches which aired within the range, in order of aired date.
+func (catalog *MatchCatalog) Between(scope ShowDateRange) []*MatchMetadata {
+	begin := 0
+	if scope.From != nil {
+		begin = sort.Search(len(catalog.aired), func(i int) bool {
+			aired := catalog.aired[i].AiredDate
+			return aired == nil || aired.Compare(scope.From) >= 0
+		})
+	}
+	end := sort.Search(len(catalog.aired), func(i int) bool {
+		aired := catalog.aired[i].AiredDate
+		return aired == nil || (scope.Until != nil && aired.Compare(scope.Until) > 0)
+	})
+	if end < begin {
+		return nil
+	}
+	return clip(catalog.aired[begin:end])
+}
+
+// Returns the matches of the season, in order of aired date.
+func (catalog *MatchCatalog) InSeason(season SeasonSlug) []*MatchMetadata {
+	return clip(catalog.seasons[season])
+}
+
+// Returns the matches the contestant appeared in, in order of aired date.  The
+// contestant is identified by their PK, or by name if the PK is not known.
+func (catalog *MatchCatalog) WithContestant(contestant ContestantID) []*MatchMetadata {
+	return clip(catalog.contestants[contestant.Key()])
+}
+
+// Returns the (1-based) position of the match within its season, ordered by
+// aired date and then by match number.  Matches without an aired date are
+// ordered after those with one.  Returns false if the match is not in the
+// catalog or has no season; see SeasonDirectory.AssignSeasons for assigning one.
+func (catalog *MatchCatalog) EpisodeOrdinal(match MatchNumber) (int, bool) {
+	episode, exists := catalog.matches[match]
+	if !exists || episode.SeasonSlug == "" {
+		return 0, false
+	}
+	season := catalog.seasons[episode.SeasonSlug]
+	return 1 + sort.Search(len(season), func(i int) bool {
+		return !airedBefore(season[i], episode)
+	}), true
+}
+
+// Iterates over all matches in the requested order.  This builds a catalog of
+// the matches first, use a MatchCatalog for repeated queries.
+func (episodes MatchIndex) Ordered(order MatchOrderEnum) iter.Seq[*MatchMetadata] {
+	return NewMatchCatalog(episodes).Ordered(order)
+}
+
+// Returns the matches which aired within the range, in order of aired date.
+// This builds a catalog of the matches first, see Ordered.
+func (episodes MatchIndex) Between(scope ShowDateRange) []*MatchMetadata {
+	return NewMatchCatalog(episodes).Between(scope)
+}
+
+// Returns the matches of the season, in order of aired date.  This builds a
+// catalog of the matches first, see Ordered.
+func (episodes MatchIndex) InSeason(season SeasonSlug) []*MatchMetadata {
+	return NewMatchCatalog(episodes).InSeason(season)
+}
+
+// Returns the matches the contestant appeared in, in order of aired date.  This
+// builds a catalog of the matches first, see Ordered.
+func (episodes MatchIndex) WithContestant(contestant ContestantID) []*MatchMetadata {
+	return NewMatchCatalog(episodes).WithContestant(contestant)
+}
+
+func (catalog *MatchCatalog) index(episode *MatchMetadata) {
+	catalog.aired = insertSorted(catalog.aired, episode, airedBefore)
+	if episode.SeasonSlug != "" {
+		catalog.seasons[episode.SeasonSlug] = insertSorted(
+			catalog.seasons[episode.SeasonSlug], episode, airedBefore)
+	}
+	for _, key := range contestantKeys(episode) {
+		catalog.contestants[key] = insertSorted(
+			catalog.contestants[key], episode, airedBefore)
+	}
+}
+
+// Removes the episode from the indexes that depend on its (mutable) fields.
+func (catalog *MatchCatalog) unindex(episode *MatchMetadata) {
+	catalog.aired = removeSorted(catalog.aired, episode, airedBefore)
+	if episode.SeasonSlug != "" {
+		catalog.seasons[episode.SeasonSlug] = removeSorted(
+			catalog.seasons[episode.SeasonSlug], episode, airedBefore)
+	}
+	for _, key := range contestantKeys(episode) {
+		catalog.contestants[key] = removeSorted(
+			catalog.contestants[key], episode, airedBefore)
+		if len(catalog.contestants[key]) == 0 {
+			delete(catalog.contestants, key)
+		}
+	}
+}
+
+// The distinct keys of the episode's contestants.
+func contestantKeys(episode *MatchMetadata) []ContestantKey {
+	keys := make([]ContestantKey, 0, len(episode.Contestants))
+	seen := make(map[ContestantKey]bool, len(episode.Contestants))
+	for _, contestant := range episode.Contestants {
+		key := contestant.Key()
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func numberedBefore(a, b *MatchMetadata) bool {
+	return a.MatchNumber < b.MatchNumber
+}
+
+func sortAired(episodes []*MatchMetadata) {
+	slices.SortFunc(episodes, func(a, b *MatchMetadata) int {
+		switch {
+		case airedBefore(a, b):
+			return -1
+		case airedBefore(b, a):
+			return +1
+		}
+		return 0
+	})
+}
+
+func insertSorted(episodes []*MatchMetadata, episode *MatchMetadata, before func(a, b *MatchMetadata) bool) []*MatchMetadata {
+	i := sort.Search(len(episodes), func(i int) bool {
+		return !before(episodes[i], episode)
+	})
+	episodes = append(episodes, nil)
+	copy(episodes[i+1:], episodes[i:])
+	episodes[i] = episode
+	return episodes
+}
+
+func removeSorted(episodes []*MatchMetadata, episode *MatchMetadata, before func(a, b *MatchMetadata) bool) []*MatchMetadata {
+	i := sort.Search(len(episodes), func(i int) bool {
+		return !before(episodes[i], episode)
+	})
+	for ; i < len(episodes); i++ {
+		if episodes[i] == episode {
+			return append(episodes[:i], episodes[i+1:]...)
+		}
+	}
+	return episodes
+}
+
+// Callers get their own copy so that they may not disturb the index.
+func clip(episodes []*MatchMetadata) []*MatchMetadata {
+	if len(episodes) == 0 {
+		return nil
+	}
+	return append([]*MatchMetadata(nil), episodes...)
+}
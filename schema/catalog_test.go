@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/catalog_test.go
+
+package schema_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func testCatalog() *schema.MatchCatalog {
+	episode := func(number int, season string, aired *schema.ShowDate, contestants ...uint64) *schema.MatchMetadata {
+		metadata := &schema.MatchMetadata{
+			MatchID: schema.MatchID{
+				MatchNumber: schema.MatchNumber(number),
+				SeasonSlug:  schema.SeasonSlug(season)},
+			AiredDate: aired}
+		for _, pk := range contestants {
+			metadata.Contestants = append(metadata.Contestants, schema.ContestantID{PK: pk})
+		}
+		return metadata
+	}
+	return schema.NewMatchCatalog(schema.MatchIndex{
+		8002: episode(8002, "36", date(2019, 9, 11), 1, 4, 5),
+		8000: episode(8000, "36", date(2019, 9, 9), 1, 2, 3),
+		8001: episode(8001, "36", date(2019, 9, 10), 1, 4, 6),
+		8100: episode(8100, "goat", date(2020, 1, 7), 7, 8, 9),
+		8500: episode(8500, "", nil, 7),
+	})
+}
+
+func numbers(episodes []*schema.MatchMetadata) []schema.MatchNumber {
+	matches := make([]schema.MatchNumber, len(episodes))
+	for i, episode := range episodes {
+		matches[i] = episode.MatchNumber
+	}
+	return matches
+}
+
+func expectMatches(t *testing.T, name string, episodes []*schema.MatchMetadata, expected ...schema.MatchNumber) {
+	t.Helper()
+	actual := numbers(episodes)
+	if len(actual) != len(expected) {
+		t.Errorf("%s = %v, expected %v", name, actual, expected)
+		return
+	}
+	for i := range actual {
+		if actual[i] != expected[i] {
+			t.Errorf("%s = %v, expected %v", name, actual, expected)
+			return
+		}
+	}
+}
+
+func TestMatchCatalogQueries(t *testing.T) {
+	catalog := testCatalog()
+	tests := []struct {
+		name     string
+		actual   []*schema.MatchMetadata
+		expected []schema.MatchNumber
+	}{
+		{"Ordered(number)", slices.Collect(catalog.Ordered(schema.ORDER_MATCH_NUMBER)),
+			[]schema.MatchNumber{8000, 8001, 8002, 8100, 8500}},
+		{"Ordered(aired)", slices.Collect(catalog.Ordered(schema.ORDER_AIRED_DATE)),
+			[]schema.MatchNumber{8000, 8001, 8002, 8100, 8500}},
+		{"Between(closed)", catalog.Between(
+			schema.ShowDateRange{From: date(2019, 9, 10), Until: date(2020, 1, 7)}),
+			[]schema.MatchNumber{8001, 8002, 8100}},
+		{"Between(since)", catalog.Between(
+			schema.ShowDateRange{From: date(2019, 9, 11)}),
+			[]schema.MatchNumber{8002, 8100}},
+		{"Between(until)", catalog.Between(
+			schema.ShowDateRange{Until: date(2019, 9, 9)}),
+			[]schema.MatchNumber{8000}},
+		{"Between(empty)", catalog.Between(
+			schema.ShowDateRange{From: date(2020, 1, 1), Until: date(2019, 1, 1)}),
+			nil},
+		{"InSeason(36)", catalog.InSeason("36"),
+			[]schema.MatchNumber{8000, 8001, 8002}},
+		{"InSeason(none)", catalog.InSeason("99"), nil},
+		{"WithContestant(1)", catalog.WithContestant(schema.ContestantID{PK: 1}),
+			[]schema.MatchNumber{8000, 8001, 8002}},
+		{"WithContestant(7)", catalog.WithContestant(schema.ContestantID{PK: 7}),
+			[]schema.MatchNumber{8100, 8500}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectMatches(t, tt.name, tt.actual, tt.expected...)
+		})
+	}
+}
+
+func TestMatchIndexQueries(t *testing.T) {
+	episodes := testCatalog().Matches()
+	expectMatches(t, "Ordered(aired)", slices.Collect(episodes.Ordered(schema.ORDER_AIRED_DATE)),
+		8000, 8001, 8002, 8100, 8500)
+	expectMatches(t, "Between(since)", episodes.Between(schema.ShowDateRange{From: date(2019, 9, 11)}),
+		8002, 8100)
+	expectMatches(t, "InSeason(36)", episodes.InSeason("36"), 8000, 8001, 8002)
+	expectMatches(t, "WithContestant(7)", episodes.WithContestant(schema.ContestantID{PK: 7}),
+		8100, 8500)
+	expectMatches(t, "Between(nil index)", schema.MatchIndex(nil).Between(schema.ShowDateRange{}))
+}
+
+func TestMatchCatalogUpdate(t *testing.T) {
+	catalog := testCatalog()
+	catalog.Update(schema.MatchMetadata{
+		MatchID:     schema.MatchID{MatchNumber: 8500, SeasonSlug: "goat"},
+		AiredDate:   date(2020, 1, 8),
+		Contestants: []schema.ContestantID{{PK: 7}, {PK: 1}}})
+	catalog.Update(schema.MatchMetadata{
+		MatchID:     schema.MatchID{MatchNumber: 7999, SeasonSlug: "35"},
+		AiredDate:   date(2019, 7, 26),
+		Contestants: []schema.ContestantID{{PK: 1}}})
+
+	if catalog.Len() != 6 {
+		t.Errorf("Len() = %d, expected 6", catalog.Len())
+	}
+	expectMatches(t, "Ordered(number)", slices.Collect(catalog.Ordered(schema.ORDER_MATCH_NUMBER)),
+		7999, 8000, 8001, 8002, 8100, 8500)
+	expectMatches(t, "Between(2020)", catalog.Between(schema.NewShowDateRange(
+		*date(2020, 1, 1), *date(2020, 12, 31))),
+		8100, 8500)
+	expectMatches(t, "InSeason(goat)", catalog.InSeason("goat"), 8100, 8500)
+	expectMatches(t, "WithContestant(1)", catalog.WithContestant(schema.ContestantID{PK: 1}),
+		7999, 8000, 8001, 8002, 8500)
+	expectMatches(t, "WithContestant(7)", catalog.WithContestant(schema.ContestantID{PK: 7}),
+		8100, 8500)
+
+	// The overwritten aired date is moved within the index.
+	catalog.Merge(schema.MatchMetadata{
+		MatchID:   schema.MatchID{MatchNumber: 8000},
+		AiredDate: date(2019, 9, 12)},
+		schema.MergePolicy{AiredDate: schema.MERGE_OVERWRITE})
+	expectMatches(t, "InSeason(36)", catalog.InSeason("36"), 8001, 8002, 8000)
+	if len(catalog.Matches()) != 6 {
+		t.Errorf("Matches() has %d episodes", len(catalog.Matches()))
+	}
+}
@@ -29,19 +29,20 @@ package schema
 #CategoryMetadata: {
   catID: #CategoryID
   title!: string
-  ...
 }
 
 // A category instance must have a title and
 // may have any number of challenges (typically five).
-#Category: #CategoryMetadata & {
+#Category: {
+  #CategoryMetadata
   challenges: [...#ChallengeMetadata]
 
   media?: [...#MediaRef]
   comments?: string
 }
 
-#CategoryAired: #CategoryMetadata & {
+#CategoryAired: {
+  #CategoryMetadata
   aired: #ShowDate
 }
 
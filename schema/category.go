@@ -24,16 +24,18 @@ package schema
 
 import _ "embed"
 
-// go:embed category.cue
+//go:embed category.cue
 var schemaCategories string
 
 type CategoryName string
 
+type CategoryID uint64
+
 type CategoryIndex map[CategoryName][]CategoryAired
 
 type CategoryMetadata struct {
 	Name       CategoryName `json:"title"`
-	CategoryID uint64       `json:"catID"`
+	CategoryID CategoryID   `json:"catID"`
 }
 
 type Category struct {
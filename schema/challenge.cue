@@ -23,14 +23,13 @@
 package schema
 
 #Value: int
-#Wager: int & >0
+#Wager: int & >=0 // zero is allowed in Final
 
 // A unique identifier for the challenge and (optionally) its ID.
 // If value is undefined it did not have an associated monetary value.
 #ChallengeMetadata: {
   qid!: uint64
   value?: #Value
-  ...
 }
 
 // Sentinel representation for a blank board cell.
@@ -43,13 +42,13 @@ UnknownChallenge: #ChallengeMetadata & { qid: 0 }
   media?: [...#MediaRef]
   category?: string
   comments?: string
-  ...
 }
 
-#Challenge: #ChallengeMetadata & #ChallengeData & {
+#Challenge: {
+  #ChallengeMetadata
+  #ChallengeData
   value?: #Value
   wager?: #Wager
-  ...
 }
 
 // A link to the media accompaniment for a challenge
@@ -68,18 +67,24 @@ UnknownChallenge: #ChallengeMetadata & { qid: 0 }
         "video/quicktime" )
 
 // The host may see the correct answer while the contestants cannot.
-#HostChallenge: #Challenge & {
+#HostChallenge: {
+  #Challenge
   correct: [...string] // excluding "what is..." preface
 }
 
 // Before answering, sometimes a player must provide a wager value first.
-#PlayerWager: #ContestantID & #ChallengeMetadata & {
+#PlayerWager: {
+  #ContestantID
+  #ChallengeMetadata
   wager!: #Wager
   comments?: string
 }
 
 // The player's response for a challenge, if entered as plain text.
 // This may instead be an audio file stored as multi-part form attachment.
-#PlayerResponse: #ContestantID & #ChallengeMetadata & {
+#PlayerResponse: {
+  #ContestantID
+  #ChallengeMetadata
   response: string
+  transcribed?: bool
 }
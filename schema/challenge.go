@@ -24,7 +24,7 @@ package schema
 
 import _ "embed"
 
-// go:embed challenge.cue
+//go:embed challenge.cue
 var schemaChallenge string
 
 type ChallengeID uint64
@@ -81,6 +81,16 @@ const (
 	MediaVideoMOV MimeType = "video/quicktime"
 )
 
+// The equivalent names used by the `filetype` column of the MediaClue table.
+var mime_filetypes = map[MimeType]string{
+	MediaImageJPG: "JPEG",
+	MediaImagePNG: "PNG",
+	MediaImageSVG: "SVG",
+	MediaAudioMP3: "MP3",
+	MediaVideoMP4: "MP4",
+	MediaVideoMOV: "MOV",
+}
+
 type HostChallenge struct {
 	Challenge `json:",inline"`
 	Correct   []string `json:"correct"`
@@ -99,4 +109,6 @@ type PlayerResponse struct {
 	ChallengeMetadata `json:",inline"`
 
 	Response string `json:"response,omitempty"`
+	// The response was transcribed from the player's speech.
+	Transcribed bool `json:"transcribed,omitempty"`
 }
@@ -0,0 +1,411 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/clue.go
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The kinds of node in a parsed clue.
+type ClueNodeEnum uint8
+
+const (
+	CLUE_TEXT ClueNodeEnum = iota
+	CLUE_EMPHASIS
+	CLUE_STRONG
+	CLUE_BREAK
+	CLUE_MEDIA
+	MaxClueNodeEnum
+)
+
+var clue_node_names = [MaxClueNodeEnum]string{
+	"text",
+	"emphasis",
+	"strong",
+	"break",
+	"media",
+}
+
+func (kind ClueNodeEnum) String() string {
+	if kind >= MaxClueNodeEnum {
+		return clue_node_names[CLUE_TEXT]
+	}
+	return clue_node_names[kind]
+}
+
+type ClueNode struct {
+	Kind ClueNodeEnum
+	// The literal text, or the description of a media embed.
+	Text string
+	// The content of emphasis and strong nodes.
+	Children []ClueNode
+	// The reference as it was written in a media embed, and the media it refers
+	// to, or nil if it is dangling.
+	Ref   string
+	Media *MediaRef
+}
+
+var (
+	ErrDanglingMedia = errors.New("clue refers to media that is not attached")
+	ErrUnusedMedia   = errors.New("attached media is not referred to by the clue")
+)
+
+// A clue parsed from its markdown, with its media references resolved.
+type ParsedClue struct {
+	Nodes []ClueNode
+	// The references in the clue that do not resolve to any of its media.
+	Dangling []string
+	// The media that the clue does not refer to.
+	Unused []MediaRef
+}
+
+// Parses the challenge's clue, resolving its media embeds against the media of
+// the challenge.  Clues are a subset of markdown: *emphasis* (or _emphasis_),
+// **strong**, newlines for line breaks and backslash escapes.  Media embeds are
+// written as images are, ![description](url) where the URL is the same as one
+// of the challenge's MediaRef, or ![description][n] for the n-th MediaRef.
+//
+// Parsing does not fail; markup that is not closed is kept as text.
+func ParseClue(challenge ChallengeData) ParsedClue {
+	parser := clueParser{media: challenge.Media, used: make([]bool, len(challenge.Media))}
+	clue := ParsedClue{Nodes: parser.parse(challenge.Clue)}
+	clue.Dangling = parser.dangling
+	for i, used := range parser.used {
+		if !used {
+			clue.Unused = append(clue.Unused, challenge.Media[i])
+		}
+	}
+	return clue
+}
+
+// Returns an error for each dangling and unused media reference, or nil.
+func (clue ParsedClue) Err() error {
+	var errs []error
+	for _, ref := range clue.Dangling {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrDanglingMedia, ref))
+	}
+	for _, media := range clue.Unused {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrUnusedMedia, media.MediaURL))
+	}
+	return errors.Join(errs...)
+}
+
+type clueParser struct {
+	media    []MediaRef
+	used     []bool
+	dangling []string
+}
+
+func (parser *clueParser) parse(text string) []ClueNode {
+	var nodes []ClueNode
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			nodes = append(nodes, ClueNode{Kind: CLUE_TEXT, Text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		switch char := text[i]; {
+		case char == '\\' && i+1 < len(text) && isClueMarkup(text[i+1]):
+			literal.WriteByte(text[i+1])
+			i += 2
+		case char == '\r':
+			i++
+		case char == '\n':
+			flush()
+			nodes = append(nodes, ClueNode{Kind: CLUE_BREAK})
+			i++
+		case strings.HasPrefix(text[i:], "!["):
+			node, width := parser.mediaEmbed(text[i:])
+			if width == 0 {
+				literal.WriteString("![")
+				i += 2
+				break
+			}
+			flush()
+			nodes = append(nodes, node)
+			i += width
+		case char == '*' || char == '_':
+			delim := text[i : i+1]
+			if strings.HasPrefix(text[i:], "**") || strings.HasPrefix(text[i:], "__") {
+				delim = text[i : i+2]
+			}
+			opens := i+len(delim) < len(text) && !isSpaceByte(text[i+len(delim)])
+			if !opens || char == '_' && i > 0 && isWordByte(text[i-1]) {
+				literal.WriteString(delim)
+				i += len(delim)
+				break
+			}
+			end := findCloser(text, i+len(delim), delim)
+			if end < 0 {
+				literal.WriteString(delim)
+				i += len(delim)
+				break
+			}
+			flush()
+			kind := CLUE_EMPHASIS
+			if len(delim) == 2 {
+				kind = CLUE_STRONG
+			}
+			nodes = append(nodes, ClueNode{
+				Kind:     kind,
+				Children: parser.parse(text[i+len(delim) : end])})
+			i = end + len(delim)
+		default:
+			literal.WriteByte(char)
+			i++
+		}
+	}
+	flush()
+	return nodes
+}
+
+// Parses a media embed at the start of the text, returning its node and how
+// many bytes it spans, or zero if it is not a media embed.
+func (parser *clueParser) mediaEmbed(text string) (ClueNode, int) {
+	alt := strings.IndexByte(text, ']')
+	if alt < 0 || alt+1 >= len(text) {
+		return ClueNode{}, 0
+	}
+	var closing byte
+	switch text[alt+1] {
+	case '(':
+		closing = ')'
+	case '[':
+		closing = ']'
+	default:
+		return ClueNode{}, 0
+	}
+	end := strings.IndexByte(text[alt+2:], closing)
+	if end < 0 {
+		return ClueNode{}, 0
+	}
+	ref := strings.TrimSpace(text[alt+2 : alt+2+end])
+	node := ClueNode{Kind: CLUE_MEDIA, Text: text[2:alt], Ref: ref}
+
+	found := -1
+	if closing == ']' {
+		if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(parser.media) {
+			found = n - 1
+		}
+	} else {
+		for i, media := range parser.media {
+			if media.MediaURL == ref {
+				found = i
+				break
+			}
+		}
+	}
+	if found >= 0 {
+		node.Media = &parser.media[found]
+		parser.used[found] = true
+	} else {
+		parser.dangling = append(parser.dangling, ref)
+	}
+	return node, alt + 3 + end
+}
+
+// Finds the delimiter which closes emphasis that was opened just before start,
+// skipping escapes and the other kinds of emphasis.  As in markdown, a closing
+// delimiter does not follow whitespace.  Returns -1 if there is
+// none or if the emphasis would be empty.
+func findCloser(text string, start int, delim string) int {
+	for i := start; i < len(text); {
+		switch {
+		case text[i] == '\\':
+			i += 2
+		case text[i] == '\n' && i+1 < len(text) && text[i+1] == '\n':
+			return -1
+		case strings.HasPrefix(text[i:], delim):
+			if len(delim) == 1 && strings.HasPrefix(text[i:], delim+delim) {
+				i += 2
+				continue
+			}
+			if i == start {
+				return -1
+			}
+			if isSpaceByte(text[i-1]) {
+				i += len(delim)
+				continue
+			}
+			return i
+		case len(delim) == 2 && text[i] == delim[0]:
+			// A single delimiter opens emphasis within the strong text.
+			if end := findCloser(text, i+1, delim[:1]); end > 0 {
+				i = end + 1
+			} else {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return -1
+}
+
+func isClueMarkup(char byte) bool {
+	return strings.IndexByte("\\*_![]()", char) >= 0
+}
+
+func isSpaceByte(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+func isWordByte(char byte) bool {
+	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' ||
+		char >= '0' && char <= '9' || char >= 0x80
+}
+
+// Renders the clue as plain text, e.g. for reading aloud or for judging.  Media
+// embeds are replaced by their description.
+func (clue ParsedClue) PlainText() string {
+	var builder strings.Builder
+	renderClue(&builder, clue.Nodes, plainClue)
+	return builder.String()
+}
+
+// Renders the clue as an HTML fragment, with media embedded by their type.  The
+// URLs are as they appear in each MediaRef, relative to the media's base URL.
+// Only http(s) and relative URLs are embedded, others (e.g. "javascript:") are
+// replaced by the media's description.
+func (clue ParsedClue) HTML() string {
+	var builder strings.Builder
+	renderClue(&builder, clue.Nodes, htmlClue)
+	return builder.String()
+}
+
+// Renders the clue for a terminal, with ANSI escapes for emphasis and a note in
+// place of each media embed.  Control characters (including any escapes) in the
+// clue's text are removed so that the text cannot control the terminal.
+func (clue ParsedClue) ANSI() string {
+	var builder strings.Builder
+	renderClue(&builder, clue.Nodes, ansiClue)
+	return builder.String()
+}
+
+type clueFormat struct {
+	text  func(string) string
+	open  [MaxClueNodeEnum]string
+	close [MaxClueNodeEnum]string
+	media func(ClueNode) string
+}
+
+func renderClue(builder *strings.Builder, nodes []ClueNode, format clueFormat) {
+	for _, node := range nodes {
+		switch node.Kind {
+		case CLUE_TEXT:
+			builder.WriteString(format.text(node.Text))
+		case CLUE_MEDIA:
+			builder.WriteString(format.media(node))
+		default:
+			builder.WriteString(format.open[node.Kind])
+			renderClue(builder, node.Children, format)
+			builder.WriteString(format.close[node.Kind])
+		}
+	}
+}
+
+var plainClue = clueFormat{
+	text: func(text string) string { return text },
+	open: [MaxClueNodeEnum]string{CLUE_BREAK: "\n"},
+	media: func(node ClueNode) string {
+		return node.Text
+	},
+}
+
+var htmlClue = clueFormat{
+	text: html.EscapeString,
+	open: [MaxClueNodeEnum]string{
+		CLUE_EMPHASIS: "<em>", CLUE_STRONG: "<strong>", CLUE_BREAK: "<br>"},
+	close: [MaxClueNodeEnum]string{
+		CLUE_EMPHASIS: "</em>", CLUE_STRONG: "</strong>"},
+	media: func(node ClueNode) string {
+		alt := html.EscapeString(node.Text)
+		if node.Media == nil || !isEmbeddableURL(node.Media.MediaURL) {
+			return alt
+		}
+		url := html.EscapeString(node.Media.MediaURL)
+		switch node.Media.MimeType {
+		case MediaImageJPG, MediaImagePNG, MediaImageSVG:
+			return fmt.Sprintf(`<img src="%s" alt="%s">`, url, alt)
+		case MediaAudioMP3:
+			return fmt.Sprintf(`<audio controls src="%s">%s</audio>`, url, alt)
+		case MediaVideoMP4, MediaVideoMOV:
+			return fmt.Sprintf(`<video controls src="%s">%s</video>`, url, alt)
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, url, alt)
+	},
+}
+
+// True for relative URLs and those with an http or https scheme.
+func isEmbeddableURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch parsed.Scheme {
+	case "", "http", "https":
+		return true
+	}
+	return false
+}
+
+// Removes the C0 and C1 control characters (such as ESC), except for line
+// breaks and tabs, and DEL.
+func stripControls(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+var ansiClue = clueFormat{
+	text: stripControls,
+	open: [MaxClueNodeEnum]string{
+		CLUE_EMPHASIS: "\x1b[3m", CLUE_STRONG: "\x1b[1m", CLUE_BREAK: "\n"},
+	close: [MaxClueNodeEnum]string{
+		CLUE_EMPHASIS: "\x1b[23m", CLUE_STRONG: "\x1b[22m"},
+	media: func(node ClueNode) string {
+		kind := "media"
+		if node.Media != nil {
+			kind, _, _ = strings.Cut(stripControls(string(node.Media.MimeType)), "/")
+			if kind == "" {
+				kind = "media"
+			}
+		}
+		if node.Text == "" {
+			return fmt.Sprintf("\x1b[4m[%s]\x1b[24m", kind)
+		}
+		return fmt.Sprintf("\x1b[4m[%s: %s]\x1b[24m", kind, stripControls(node.Text))
+	},
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/clue_test.go
+
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func mediaChallenge(clue string) schema.ChallengeData {
+	return schema.ChallengeData{
+		Clue: clue,
+		Media: []schema.MediaRef{
+			{MimeType: schema.MediaImageJPG, MediaURL: "img/tower.jpg"},
+			{MimeType: schema.MediaAudioMP3, MediaURL: "audio/bells.mp3"},
+		}}
+}
+
+func TestParseClue(t *testing.T) {
+	tests := []struct {
+		name     string
+		clue     string
+		plain    string
+		html     string
+		ansi     string
+		dangling []string
+		unused   int
+	}{
+		{"plain", "This tower leans & tilts", "This tower leans & tilts",
+			"This tower leans &amp; tilts", "This tower leans & tilts", nil, 2},
+		{"emphasis", "The *Mona Lisa* hangs in the **Louvre**",
+			"The Mona Lisa hangs in the Louvre",
+			"The <em>Mona Lisa</em> hangs in the <strong>Louvre</strong>",
+			"The \x1b[3mMona Lisa\x1b[23m hangs in the \x1b[1mLouvre\x1b[22m", nil, 2},
+		{"nested", "**a _very_ big** deal", "a very big deal",
+			"<strong>a <em>very</em> big</strong> deal",
+			"\x1b[1ma \x1b[3mvery\x1b[23m big\x1b[22m deal", nil, 2},
+		{"unclosed", "5 * 3 is *15 and snake_case_name", "5 * 3 is *15 and snake_case_name",
+			"5 * 3 is *15 and snake_case_name", "5 * 3 is *15 and snake_case_name", nil, 2},
+		{"escaped", `\*not emphasis\*`, "*not emphasis*", "*not emphasis*", "*not emphasis*", nil, 2},
+		{"break", "Line one\nLine two", "Line one\nLine two", "Line one<br>Line two",
+			"Line one\nLine two", nil, 2},
+		{"media", "![The tower](img/tower.jpg)\nListen: ![bells][2]",
+			"The tower\nListen: bells",
+			`<img src="img/tower.jpg" alt="The tower"><br>Listen: <audio controls src="audio/bells.mp3">bells</audio>`,
+			"\x1b[4m[image: The tower]\x1b[24m\nListen: \x1b[4m[audio: bells]\x1b[24m", nil, 0},
+		{"dangling", "See ![map](img/map.png) and ![][3]", "See map and ",
+			"See map and ", "See \x1b[4m[media: map]\x1b[24m and \x1b[4m[media]\x1b[24m",
+			[]string{"img/map.png", "3"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clue := schema.ParseClue(mediaChallenge(tt.clue))
+			if got := clue.PlainText(); got != tt.plain {
+				t.Errorf("PlainText() = %q, want %q", got, tt.plain)
+			}
+			if got := clue.HTML(); got != tt.html {
+				t.Errorf("HTML() = %q, want %q", got, tt.html)
+			}
+			if got := clue.ANSI(); got != tt.ansi {
+				t.Errorf("ANSI() = %q, want %q", got, tt.ansi)
+			}
+			if len(clue.Dangling) != len(tt.dangling) {
+				t.Fatalf("Dangling = %q, want %q", clue.Dangling, tt.dangling)
+			}
+			for i, ref := range tt.dangling {
+				if clue.Dangling[i] != ref {
+					t.Errorf("Dangling[%d] = %q, want %q", i, clue.Dangling[i], ref)
+				}
+			}
+			if len(clue.Unused) != tt.unused {
+				t.Errorf("Unused = %v, want %d of them", clue.Unused, tt.unused)
+			}
+		})
+	}
+}
+
+func TestClueHTMLURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		html string
+	}{
+		{"relative", "img/tower.jpg", `<img src="img/tower.jpg" alt="tower">`},
+		{"https", "https://example.com/tower.jpg", `<img src="https://example.com/tower.jpg" alt="tower">`},
+		{"http", "HTTP://example.com/tower.jpg", `<img src="HTTP://example.com/tower.jpg" alt="tower">`},
+		{"javascript", "javascript:alert(1)", "tower"},
+		{"mixed case javascript", "JavaScript:alert(1)", "tower"},
+		{"data", "data:image/svg+xml;base64,PHN2Zz4=", "tower"},
+		{"leading space", " javascript:alert(1)", "tower"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clue := schema.ParseClue(schema.ChallengeData{
+				Clue:  "![tower][1]",
+				Media: []schema.MediaRef{{MimeType: schema.MediaImagePNG, MediaURL: tt.url}}})
+			if got := clue.HTML(); got != tt.html {
+				t.Errorf("HTML() = %q, want %q", got, tt.html)
+			}
+		})
+	}
+}
+
+func TestClueANSIControls(t *testing.T) {
+	clue := schema.ParseClue(mediaChallenge(
+		"*Bells*\x1b]0;title\x07 ring\u009b2J ![\x1b[31mred][2]"))
+	want := "\x1b[3mBells\x1b[23m]0;title ring2J \x1b[4m[audio: [31mred]\x1b[24m"
+	if got := clue.ANSI(); got != want {
+		t.Errorf("ANSI() = %q, want %q", got, want)
+	}
+}
+
+func TestClueANSIMimeControls(t *testing.T) {
+	challenge := mediaChallenge("![bells][1]")
+	challenge.Media[0].MimeType = "\x1b]0;title\x07image/png"
+	want := "\x1b[4m[]0;titleimage: bells]\x1b[24m"
+	if got := schema.ParseClue(challenge).ANSI(); got != want {
+		t.Errorf("ANSI() = %q, want %q", got, want)
+	}
+}
+
+func TestParseClueNodes(t *testing.T) {
+	clue := schema.ParseClue(mediaChallenge("*See* ![it][1]"))
+	if len(clue.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %+v", clue.Nodes)
+	}
+	kinds := []schema.ClueNodeEnum{schema.CLUE_EMPHASIS, schema.CLUE_TEXT, schema.CLUE_MEDIA}
+	for i, kind := range kinds {
+		if clue.Nodes[i].Kind != kind {
+			t.Errorf("node %d is %s, want %s", i, clue.Nodes[i].Kind, kind)
+		}
+	}
+	if media := clue.Nodes[2].Media; media == nil || media.MediaURL != "img/tower.jpg" {
+		t.Errorf("media embed resolved to %v", media)
+	}
+	if clue.Nodes[0].Children[0].Text != "See" {
+		t.Errorf("emphasis contains %+v", clue.Nodes[0].Children)
+	}
+}
+
+func TestParsedClueErr(t *testing.T) {
+	if err := schema.ParseClue(mediaChallenge("![a][1] ![b][2]")).Err(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	err := schema.ParseClue(mediaChallenge("![a][1] ![c](missing.png)")).Err()
+	if !errors.Is(err, schema.ErrDanglingMedia) || !errors.Is(err, schema.ErrUnusedMedia) {
+		t.Errorf("expected dangling and unused media errors, got %v", err)
+	}
+}
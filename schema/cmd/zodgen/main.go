@@ -0,0 +1,462 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/cmd/zodgen/main.go
+
+// Generates the @zod/mini schemas (schema/*.ts) from the exported Go types in
+// the schema package, using reflection over their structure and json tags.
+//
+//	go generate github.com/kevindamm/q-party/schema
+//
+// All definitions are written to a single module (schema.gen.ts) to avoid
+// cyclic imports between the TypeScript modules, then each of the per-file
+// modules (season.ts, match.ts, ...) re-exports the definitions that belong to
+// its namesake Go file.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+// The TypeScript module that all definitions are generated into.
+const GENERATED_MODULE = "schema.gen"
+
+// The types that are exported, grouped by the Go source file that defines them.
+// Within each group, the order is the order that definitions are written in,
+// though dependencies are always written before the types that depend on them.
+type module struct {
+	name  string
+	types []reflect.Type
+}
+
+var modules = []module{
+	{"show_date", []reflect.Type{
+		reflect.TypeFor[schema.ShowDate](),
+		reflect.TypeFor[schema.ShowDateRange](),
+	}},
+	{"season", []reflect.Type{
+		reflect.TypeFor[schema.SeasonSlug](),
+		reflect.TypeFor[schema.SeasonID](),
+		reflect.TypeFor[schema.SeasonMetadata](),
+		reflect.TypeFor[schema.SeasonIndex](),
+		reflect.TypeFor[schema.SeasonDirectory](),
+		reflect.TypeFor[schema.Season](),
+	}},
+	{"match", []reflect.Type{
+		reflect.TypeFor[schema.MatchNumber](),
+		reflect.TypeFor[schema.MatchID](),
+		reflect.TypeFor[schema.MatchMetadata](),
+		reflect.TypeFor[schema.MatchIndex](),
+		reflect.TypeFor[schema.MatchStats](),
+	}},
+	{"category", []reflect.Type{
+		reflect.TypeFor[schema.CategoryName](),
+		reflect.TypeFor[schema.CategoryID](),
+		reflect.TypeFor[schema.CategoryMetadata](),
+		reflect.TypeFor[schema.Category](),
+		reflect.TypeFor[schema.CategoryAired](),
+		reflect.TypeFor[schema.CategoryIndex](),
+	}},
+	{"challenge", []reflect.Type{
+		reflect.TypeFor[schema.ChallengeID](),
+		reflect.TypeFor[schema.Value](),
+		reflect.TypeFor[schema.Wager](),
+		reflect.TypeFor[schema.ChallengeMetadata](),
+		reflect.TypeFor[schema.MimeType](),
+		reflect.TypeFor[schema.MediaRef](),
+		reflect.TypeFor[schema.ChallengeData](),
+		reflect.TypeFor[schema.Challenge](),
+		reflect.TypeFor[schema.HostChallenge](),
+		reflect.TypeFor[schema.PlayerWager](),
+		reflect.TypeFor[schema.PlayerResponse](),
+	}},
+	{"contestant", []reflect.Type{
+		reflect.TypeFor[schema.ContestantID](),
+		reflect.TypeFor[schema.Contestant](),
+		reflect.TypeFor[schema.Appearance](),
+		reflect.TypeFor[schema.Career](),
+	}},
+	{"data_quality", []reflect.Type{
+		reflect.TypeFor[schema.DataQualityEnum](),
+		reflect.TypeFor[schema.DataQuality](),
+		reflect.TypeFor[schema.DataQualityJudgement](),
+	}},
+	{"round", []reflect.Type{
+		reflect.TypeFor[schema.RoundEnum](),
+		reflect.TypeFor[schema.RoundID](),
+		reflect.TypeFor[schema.BoardPosition](),
+		reflect.TypeFor[schema.Board](),
+		reflect.TypeFor[schema.BoardLayout](),
+		reflect.TypeFor[schema.BoardSelection](),
+		reflect.TypeFor[schema.SelectionOutcome](),
+		reflect.TypeFor[schema.BoardState](),
+	}},
+	{"record", []reflect.Type{
+		reflect.TypeFor[schema.BoardChallenge](),
+		reflect.TypeFor[schema.RecordedOutcome](),
+		reflect.TypeFor[schema.RoundRecord](),
+		reflect.TypeFor[schema.FinalResponse](),
+		reflect.TypeFor[schema.FinalRecord](),
+		reflect.TypeFor[schema.ScoreTimeline](),
+		reflect.TypeFor[schema.MatchRecord](),
+	}},
+}
+
+// Values (not schemas) exported along with a module's types, as in the CUE.
+var moduleConstants = map[string]string{
+	"challenge": "// Sentinel representation for a blank board cell.\n" +
+		"export const UnknownChallenge = { qid: 0 }\n",
+}
+
+// Go types carry no value constraints, these are added to the generated schema
+// for the named type (e.g. "Wager") or for a struct field ("ShowDate.year").
+var refinements = map[string]string{
+	"SeasonSlug":           `z.regex(/^[a-zA-Z][0-9a-zA-Z_-]*$/)`,
+	"MatchNumber":          `z.positive()`,
+	"Wager":                `z.nonnegative()`,
+	"DataQualityEnum":      fmt.Sprintf(`z.lt(%d)`, schema.MaxDataQualityEnum),
+	"RoundEnum":            fmt.Sprintf(`z.nonnegative(), z.lt(%d)`, schema.MaxRoundEnum),
+	"ShowDate.year":        `z.minimum(1980)`,
+	"ShowDate.month":       `z.minimum(1), z.maximum(12)`,
+	"ShowDate.day":         `z.minimum(1), z.maximum(31)`,
+	"BoardPosition.column": `z.positive()`,
+	"BoardPosition.index":  `z.positive()`,
+}
+
+// Reflection cannot enumerate constants, so string enumerations are listed here.
+var enumerations = map[reflect.Type][]string{
+	reflect.TypeFor[schema.MimeType](): {
+		string(schema.MediaImageJPG),
+		string(schema.MediaImagePNG),
+		string(schema.MediaImageSVG),
+		string(schema.MediaAudioMP3),
+		string(schema.MediaVideoMP4),
+		string(schema.MediaVideoMOV),
+	},
+}
+
+func main() {
+	outdir := flag.String("out", ".", "directory to write the .ts modules into")
+	flag.Parse()
+
+	files, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for filename, contents := range files {
+		path := filepath.Join(*outdir, filename)
+		if err := os.WriteFile(path, contents, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// Returns the contents of each generated file, indexed by its filename.
+func generate() (map[string][]byte, error) {
+	gen := newGenerator()
+	for _, module := range modules {
+		for _, typ := range module.types {
+			if err := gen.define(typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	files := make(map[string][]byte)
+	var buffer bytes.Buffer
+	writeHeader(&buffer, GENERATED_MODULE+".ts")
+	buffer.WriteString("import * as z from \"@zod/mini\"\n")
+	for _, definition := range gen.definitions {
+		buffer.WriteString("\n")
+		buffer.WriteString(definition)
+	}
+	files[GENERATED_MODULE+".ts"] = buffer.Bytes()
+
+	for _, module := range modules {
+		var buffer bytes.Buffer
+		writeHeader(&buffer, module.name+".ts")
+		buffer.WriteString("export {\n")
+		for _, typ := range module.types {
+			fmt.Fprintf(&buffer, "  %s,\n", typ.Name())
+		}
+		fmt.Fprintf(&buffer, "} from \"./%s\"\n", GENERATED_MODULE)
+		if constants, ok := moduleConstants[module.name]; ok {
+			buffer.WriteString("\n" + constants)
+		}
+		files[module.name+".ts"] = buffer.Bytes()
+	}
+	return files, nil
+}
+
+func writeHeader(buffer *bytes.Buffer, filename string) {
+	buffer.WriteString(LICENSE_HEADER)
+	fmt.Fprintf(buffer, "//\n// github:kevindamm/q-party/schema/%s\n", filename)
+	buffer.WriteString("\n// Code generated by zodgen from the schema Go types. DO NOT EDIT.\n\n")
+}
+
+type generator struct {
+	exported    map[reflect.Type]bool
+	defined     map[reflect.Type]bool
+	visiting    map[reflect.Type]bool
+	definitions []string
+}
+
+func newGenerator() *generator {
+	gen := &generator{
+		exported: make(map[reflect.Type]bool),
+		defined:  make(map[reflect.Type]bool),
+		visiting: make(map[reflect.Type]bool),
+	}
+	for _, module := range modules {
+		for _, typ := range module.types {
+			gen.exported[typ] = true
+		}
+	}
+	return gen
+}
+
+// Adds the definition for typ (and, first, any exported types it depends on).
+func (gen *generator) define(typ reflect.Type) error {
+	if gen.defined[typ] {
+		return nil
+	}
+	if gen.visiting[typ] {
+		return fmt.Errorf("recursive type %s cannot be generated", typ.Name())
+	}
+	gen.visiting[typ] = true
+	defer delete(gen.visiting, typ)
+
+	for _, dependency := range gen.dependencies(typ, true) {
+		if err := gen.define(dependency); err != nil {
+			return err
+		}
+	}
+	expr, err := gen.expr(typ, true)
+	if err != nil {
+		return err
+	}
+	gen.definitions = append(gen.definitions,
+		fmt.Sprintf("export const %s = %s\n", typ.Name(), expr))
+	gen.defined[typ] = true
+	return nil
+}
+
+// Finds the exported types that are referenced by the definition of typ.
+func (gen *generator) dependencies(typ reflect.Type, top bool) []reflect.Type {
+	if !top && gen.exported[typ] {
+		return []reflect.Type{typ}
+	}
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return gen.dependencies(typ.Elem(), false)
+	case reflect.Map:
+		return append(gen.dependencies(typ.Key(), false),
+			gen.dependencies(typ.Elem(), false)...)
+	case reflect.Struct:
+		var dependencies []reflect.Type
+		for i := range typ.NumField() {
+			dependencies = append(dependencies,
+				gen.dependencies(typ.Field(i).Type, false)...)
+		}
+		return dependencies
+	}
+	return nil
+}
+
+var reDigits = regexp.MustCompile(`^\d+$`)
+
+// Returns the zod expression for typ.  Exported types are referred to by name
+// unless this is the (top-level) definition of that type.  Any refinements are
+// added to the checks of the type.
+func (gen *generator) expr(typ reflect.Type, top bool, refined ...string) (string, error) {
+	if !top && gen.exported[typ] {
+		return withChecks(typ.Name(), refined), nil
+	}
+
+	var expr string
+	var checks []string
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return gen.expr(typ.Elem(), false, refined...)
+	case reflect.Bool:
+		expr = "z.boolean()"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		expr = "z.int()"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		expr = "z.int()"
+		checks = append(checks, "z.nonnegative()")
+	case reflect.Float32, reflect.Float64:
+		expr = "z.number()"
+	case reflect.String:
+		expr = "z.string()"
+		if values, ok := enumerations[typ]; ok {
+			quoted := make([]string, len(values))
+			for i, value := range values {
+				quoted[i] = fmt.Sprintf("%q", value)
+			}
+			expr = fmt.Sprintf("z.enum([\n  %s,\n])", strings.Join(quoted, ",\n  "))
+		}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			// encoding/json represents byte slices as base64 strings.
+			expr = "z.base64()"
+			break
+		}
+		elem, err := gen.expr(typ.Elem(), false)
+		if err != nil {
+			return "", err
+		}
+		expr = fmt.Sprintf("z.array(%s)", elem)
+	case reflect.Map:
+		key := "z.string()"
+		switch typ.Key().Kind() {
+		case reflect.String:
+			if gen.exported[typ.Key()] {
+				key = typ.Key().Name()
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// JSON object keys are always strings, integers are written in decimal.
+			key = fmt.Sprintf("z.string().check(z.regex(/%s/))", reDigits)
+		default:
+			return "", fmt.Errorf("unsupported map key type %s", typ.Key())
+		}
+		elem, err := gen.expr(typ.Elem(), false)
+		if err != nil {
+			return "", err
+		}
+		expr = fmt.Sprintf("z.record(%s, %s)", key, elem)
+	case reflect.Struct:
+		return gen.object(typ)
+	default:
+		return "", fmt.Errorf("unsupported type %s", typ)
+	}
+
+	checks = append(checks, refined...)
+	if !top {
+		return withChecks(expr, checks), nil
+	}
+	if refinement, ok := refinements[typ.Name()]; ok {
+		checks = append(checks, refinement)
+	}
+	expr = withChecks(expr, checks)
+	// Only the scalar types are branded, collections are structural.
+	if typ.Name() != "" && enumerations[typ] == nil {
+		switch typ.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array:
+			if typ.Elem().Kind() != reflect.Uint8 {
+				break
+			}
+			fallthrough
+		default:
+			expr += fmt.Sprintf(".brand(%q)", typ.Name())
+		}
+	}
+	return expr, nil
+}
+
+func withChecks(expr string, checks []string) string {
+	if len(checks) == 0 {
+		return expr
+	}
+	// A positive refinement of an unsigned type subsumes its nonnegative check.
+	if slices.Contains(checks, "z.positive()") {
+		checks = slices.DeleteFunc(slices.Clone(checks), func(check string) bool {
+			return check == "z.nonnegative()"
+		})
+	}
+	return fmt.Sprintf("%s.check(%s)", expr, strings.Join(checks, ", "))
+}
+
+// Structs with embedded (inlined) structs extend the first of those, including
+// the shape of any others, followed by the struct's own fields.
+func (gen *generator) object(typ reflect.Type) (string, error) {
+	var embedded []string
+	var fields []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			expr, err := gen.expr(field.Type, false)
+			if err != nil {
+				return "", err
+			}
+			embedded = append(embedded, expr)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		var refined []string
+		if refinement, ok := refinements[typ.Name()+"."+name]; ok {
+			refined = append(refined, refinement)
+		}
+		expr, err := gen.expr(fieldType, false, refined...)
+		if err != nil {
+			return "", err
+		}
+
+		// Same as encoding/json, omitempty has no effect on struct values.
+		optional := field.Type.Kind() == reflect.Pointer ||
+			slices.ContainsFunc(strings.Split(options, ","), func(option string) bool {
+				return option == "omitzero" ||
+					(option == "omitempty" && fieldType.Kind() != reflect.Struct)
+			})
+		if optional {
+			expr = fmt.Sprintf("z.optional(%s)", expr)
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s,", name, expr))
+	}
+
+	var shape strings.Builder
+	shape.WriteString("{\n")
+	for _, other := range embedded[min(1, len(embedded)):] {
+		fmt.Fprintf(&shape, "  ...%s.shape,\n", other)
+	}
+	for _, field := range fields {
+		fmt.Fprintf(&shape, "  %s\n", field)
+	}
+	shape.WriteString("}")
+
+	if len(embedded) == 0 {
+		return fmt.Sprintf("z.object(%s)", shape.String()), nil
+	}
+	return fmt.Sprintf("z.extend(%s, %s)", embedded[0], shape.String()), nil
+}
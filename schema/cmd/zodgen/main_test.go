@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/cmd/zodgen/main_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Fails when the .ts files have not been regenerated after changing the types.
+func TestGeneratedFilesCurrent(t *testing.T) {
+	files, err := generate()
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for filename, want := range files {
+		got, err := os.ReadFile(filepath.Join("..", "..", filename))
+		if err != nil {
+			t.Errorf("%s: %v", filename, err)
+			continue
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s is out of date, run `go generate` in schema/", filename)
+		}
+	}
+}
+
+// Each module must keep exporting what TypeScript consumers already import.
+func TestModuleExports(t *testing.T) {
+	exports := map[string][]string{
+		"category.ts": {"CategoryID", "CategoryMetadata", "CategoryIndex", "Category", "CategoryAired"},
+		"challenge.ts": {"Value", "Wager", "ChallengeMetadata", "UnknownChallenge", "MediaRef",
+			"ChallengeData", "Challenge", "HostChallenge", "PlayerWager", "PlayerResponse"},
+		"contestant.ts":   {"ContestantID", "Contestant", "Appearance", "Career"},
+		"data_quality.ts": {"DataQualityEnum", "DataQuality", "DataQualityJudgement"},
+		"match.ts":        {"MatchNumber", "MatchID", "MatchMetadata", "MatchIndex", "MatchStats"},
+		"round.ts": {"RoundEnum", "RoundID", "BoardPosition", "Board", "BoardSelection",
+			"SelectionOutcome", "BoardState"},
+		"season.ts": {"SeasonSlug", "SeasonID", "SeasonMetadata", "SeasonIndex",
+			"SeasonDirectory", "Season"},
+		"show_date.ts": {"ShowDate", "ShowDateRange"},
+	}
+	files, err := generate()
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for filename, names := range exports {
+		contents := string(files[filename])
+		for _, name := range names {
+			if !strings.Contains(contents, "\n  "+name+",\n") &&
+				!strings.Contains(contents, "\nexport const "+name+" = ") {
+				t.Errorf("%s no longer exports %s", filename, name)
+			}
+		}
+	}
+}
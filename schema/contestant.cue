@@ -26,11 +26,11 @@ package schema
 #ContestantID: {
   cid!: uint64
   name?: string
-  ...
 }
 
 // Additional details about the contestant.
-#Contestant: #ContestantID & {
+#Contestant: {
+  #ContestantID
   name!: string
   occupation?: string
   residence?: string
@@ -39,12 +39,14 @@ package schema
 }
 
 // An appearance is the joining of a contestant and an episode.
-#Appearance: #ContestantID & {
+#Appearance: {
+  #ContestantID
   match: #MatchID
 }
 
 // The episodes that a contestant has appeared in and their total winnings.
-#Career: #ContestantID & {
+#Career: {
+  #ContestantID
   matches: [...#MatchID]
   winnings: #Value
 }
@@ -24,7 +24,7 @@ package schema
 
 import _ "embed"
 
-// go:embed contestant.cue
+//go:embed contestant.cue
 var schemaContestants string
 
 type ContestantID struct {
@@ -32,6 +32,21 @@ type ContestantID struct {
 	Name string `json:"name,omitempty"`
 }
 
+// Identifies a contestant when merging, indexing or scoring.  Only one of the
+// fields is set, the PK if it is known and otherwise the name.
+type ContestantKey struct {
+	PK   uint64
+	Name string
+}
+
+// Contestants are identified by their PK, or by name if it has not been set.
+func (contestant ContestantID) Key() ContestantKey {
+	if contestant.PK == 0 {
+		return ContestantKey{Name: contestant.Name}
+	}
+	return ContestantKey{PK: contestant.PK}
+}
+
 type Contestant struct {
 	ContestantID `json:",inline"`
 
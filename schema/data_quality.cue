@@ -38,9 +38,10 @@ _dq_names: [...string] & [
 #DataQuality: {
   dqID: #DataQualityEnum & <len(_dq_names)
   quality: _dq_names[dqID]
-  ...
 }
 
-#DataQualityJudgement: #ChallengeMetadata & #DataQuality & {
+#DataQualityJudgement: {
+  #ChallengeMetadata
+  #DataQuality
   comments?: string
 }
@@ -24,11 +24,63 @@ package schema
 
 import _ "embed"
 
-// go:embed data_quality.cue
+//go:embed data_quality.cue
 var schemaDataQuality string
 
 type DataQualityEnum uint8
 
+// Levels of confidence in the accuracy of a challenge's correct answer(s).
+const (
+	QUALITY_NEEDS_REVIEW DataQualityEnum = iota
+	QUALITY_ENTIRELY_INCORRECT
+	QUALITY_RECENTLY_INCORRECT
+	QUALITY_SUSPECTED_OUTDATED
+	QUALITY_NEEDS_MINOR_CHANGE
+	QUALITY_DISAGREEMENT
+	QUALITY_CORRECT
+	QUALITY_CONFIRMED_CORRECT
+	MaxDataQualityEnum
+)
+
+var dq_names = [MaxDataQualityEnum]string{
+	"Needs Review",
+	"Entirely Incorrect",
+	"Recently Incorrect",
+	"Suspected Outdated",
+	"Needs Minor Change",
+	"Disagreement",
+	"Correct",
+	"Confirmed Correct"}
+
+func (quality DataQualityEnum) String() string {
+	if quality >= MaxDataQualityEnum {
+		return dq_names[QUALITY_NEEDS_REVIEW]
+	}
+	return dq_names[quality]
+}
+
+// The enum values are not in order of confidence (the zero value is for those
+// which have not been reviewed), this orders them from least to most trusted.
+var dq_ranks = [MaxDataQualityEnum]int{
+	QUALITY_ENTIRELY_INCORRECT: 0,
+	QUALITY_RECENTLY_INCORRECT: 1,
+	QUALITY_SUSPECTED_OUTDATED: 2,
+	QUALITY_NEEDS_REVIEW:       3,
+	QUALITY_DISAGREEMENT:       4,
+	QUALITY_NEEDS_MINOR_CHANGE: 5,
+	QUALITY_CORRECT:            6,
+	QUALITY_CONFIRMED_CORRECT:  7,
+}
+
+// Returns the confidence in the quality, for comparing two qualities.  Unknown
+// values are ranked the same as QUALITY_NEEDS_REVIEW.
+func (quality DataQualityEnum) Rank() int {
+	if quality >= MaxDataQualityEnum {
+		return dq_ranks[QUALITY_NEEDS_REVIEW]
+	}
+	return dq_ranks[quality]
+}
+
 type DataQuality struct {
 	QualityID   DataQualityEnum `json:"dqID"`
 	QualityName string          `json:"quality"`
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/generator.go
+
+package schema
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+var ErrBoardConstraints = errors.New("not enough categories meet the constraints")
+
+// A category that may be chosen for a generated board, along with the details
+// (from the challenges database) that the constraints are checked against.
+type CandidateCategory struct {
+	CategoryMetadata
+	Theme      CategoryThemeEnum
+	Aired      *ShowDate // nil if the category has not been aired
+	Challenges []CandidateChallenge
+}
+
+type CandidateChallenge struct {
+	HostChallenge
+	Difficulty uint8 // 1 (easiest) to 5, as in ChallengeDifficultyEnum, 0 if unknown
+	Quality    DataQualityEnum
+}
+
+// Constraints for GenerateBoard.  The zero value is a standard 6x5 board for
+// the first round, from any category with enough challenges.
+type BoardConstraints struct {
+	// Defaults to ROUND_SINGLE, its rules give the number of rows.
+	Round RoundEnum
+	// Defaults to the number of columns in the round's rules.
+	Columns uint
+
+	// Only categories which aired within this range (when it has any bounds).
+	Aired ShowDateRange
+	// Only challenges within this difficulty band, either bound may be 0.
+	MinDifficulty, MaxDifficulty uint8
+	// The number of columns required from each theme, the remaining columns are
+	// taken from any theme.
+	Themes map[CategoryThemeEnum]uint
+	// Challenges that these players have already seen.
+	Exclude map[ChallengeID]bool
+	// Only challenges ranked at least this quality (see DataQualityEnum.Rank).
+	// The zero value still excludes those known to be incorrect or outdated.
+	MinQuality DataQualityEnum
+
+	// The same seed (with the same pool) always generates the same board.
+	Seed uint64
+}
+
+// Assembles a board from the categories in the pool.  Each column's challenges
+// are ordered by difficulty (easiest at the top), and no two columns have the
+// same title.  The returned round has no history yet.
+func GenerateBoard(pool []CandidateCategory, constraints BoardConstraints) (*RoundRecord, error) {
+	if constraints.Round == ROUND_UNKNOWN {
+		constraints.Round = ROUND_SINGLE
+	}
+	rules := constraints.Round.Rules()
+	columns, rows := constraints.Columns, rules.Rows
+	if columns == 0 {
+		columns = rules.Columns
+	}
+	random := rand.New(rand.NewPCG(constraints.Seed, 0x7175697a))
+
+	// The eligible challenges of each eligible category, in random order.
+	var eligible []CandidateCategory
+	for _, category := range pool {
+		if !constraints.allowsCategory(category) {
+			continue
+		}
+		challenges := make([]CandidateChallenge, 0, len(category.Challenges))
+		for _, challenge := range category.Challenges {
+			if constraints.allowsChallenge(challenge) {
+				challenges = append(challenges, challenge)
+			}
+		}
+		if uint(len(challenges)) >= rows {
+			category.Challenges = challenges
+			eligible = append(eligible, category)
+		}
+	}
+	random.Shuffle(len(eligible), func(i, j int) {
+		eligible[i], eligible[j] = eligible[j], eligible[i]
+	})
+
+	chosen := make([]CandidateCategory, 0, columns)
+	titles := make(map[CategoryName]bool)
+	choose := func(theme CategoryThemeEnum, anyTheme bool, count uint) uint {
+		for i := 0; count > 0 && i < len(eligible); i++ {
+			category := eligible[i]
+			if titles[category.Name] || (!anyTheme && category.Theme != theme) {
+				continue
+			}
+			titles[category.Name] = true
+			chosen = append(chosen, category)
+			count--
+		}
+		return count
+	}
+	themes := make([]CategoryThemeEnum, 0, len(constraints.Themes))
+	for theme := range constraints.Themes {
+		themes = append(themes, theme)
+	}
+	slices.Sort(themes)
+	for _, theme := range themes {
+		if short := choose(theme, false, constraints.Themes[theme]); short > 0 {
+			return nil, fmt.Errorf("%w: %d more needed with theme %d",
+				ErrBoardConstraints, short, theme)
+		}
+	}
+	if uint(len(chosen)) > columns {
+		return nil, fmt.Errorf("%w: themes require %d of %d columns",
+			ErrBoardConstraints, len(chosen), columns)
+	}
+	if short := choose(UNKNOWN_CATEGORY, true, columns-uint(len(chosen))); short > 0 {
+		return nil, fmt.Errorf("%w: %d more needed", ErrBoardConstraints, short)
+	}
+	random.Shuffle(len(chosen), func(i, j int) {
+		chosen[i], chosen[j] = chosen[j], chosen[i]
+	})
+
+	round := &RoundRecord{Board: Board{RoundID: RoundID{Round: constraints.Round}}}
+	for column, category := range chosen {
+		round.Columns = append(round.Columns, category.CategoryMetadata)
+
+		challenges := category.Challenges
+		random.Shuffle(len(challenges), func(i, j int) {
+			challenges[i], challenges[j] = challenges[j], challenges[i]
+		})
+		challenges = challenges[:rows]
+		slices.SortStableFunc(challenges, func(a, b CandidateChallenge) int {
+			return cmp.Compare(a.Difficulty, b.Difficulty)
+		})
+		for index, challenge := range challenges {
+			round.Challenges = append(round.Challenges, BoardChallenge{
+				BoardPosition: BoardPosition{Column: uint(column + 1), Index: uint(index + 1)},
+				HostChallenge: challenge.HostChallenge,
+			})
+		}
+	}
+	return round, nil
+}
+
+func (constraints BoardConstraints) allowsCategory(category CandidateCategory) bool {
+	if constraints.Aired.From == nil && constraints.Aired.Until == nil {
+		return true
+	}
+	return category.Aired != nil && constraints.Aired.Contains(*category.Aired)
+}
+
+func (constraints BoardConstraints) allowsChallenge(challenge CandidateChallenge) bool {
+	switch {
+	case constraints.Exclude[challenge.ChallengeID]:
+		return false
+	case challenge.Quality.Rank() < constraints.MinQuality.Rank():
+		return false
+	case constraints.MinDifficulty != 0 && challenge.Difficulty < constraints.MinDifficulty:
+		return false
+	case constraints.MaxDifficulty != 0 &&
+		(challenge.Difficulty == 0 || challenge.Difficulty > constraints.MaxDifficulty):
+		return false
+	}
+	return true
+}
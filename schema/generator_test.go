@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/generator_test.go
+
+package schema_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+// Ten categories alternating between two themes, each with seven challenges.
+// The last category has the same title as the first, the fifth has not aired.
+func testPool() []schema.CandidateCategory {
+	pool := make([]schema.CandidateCategory, 10)
+	for i := range pool {
+		category := &pool[i]
+		category.CategoryID = schema.CategoryID(i + 1)
+		category.Name = schema.CategoryName(fmt.Sprintf("CATEGORY %d", i%9))
+		category.Theme = schema.CATEGORY_SCIENCE_NATURE
+		if i%2 == 1 {
+			category.Theme = schema.CATEGORY_HISTORY_ROYALTY
+		}
+		if i != 4 {
+			category.Aired = date(2000+i, 1, 1)
+		}
+		for j := range 7 {
+			var challenge schema.CandidateChallenge
+			challenge.ChallengeID = schema.ChallengeID(100*(i+1) + j)
+			challenge.Difficulty = uint8(5 - j%5)
+			challenge.Quality = schema.QUALITY_CORRECT
+			if j == 6 {
+				challenge.Quality = schema.QUALITY_DISAGREEMENT
+			}
+			category.Challenges = append(category.Challenges, challenge)
+		}
+	}
+	return pool
+}
+
+func TestGenerateBoard(t *testing.T) {
+	seen := map[schema.ChallengeID]bool{100: true, 201: true}
+	round, err := schema.GenerateBoard(testPool(), schema.BoardConstraints{
+		Round:      schema.ROUND_SINGLE,
+		Themes:     map[schema.CategoryThemeEnum]uint{schema.CATEGORY_HISTORY_ROYALTY: 4},
+		Exclude:    seen,
+		MinQuality: schema.QUALITY_CORRECT,
+		Seed:       1984})
+	if err != nil {
+		t.Fatalf("GenerateBoard() error = %v", err)
+	}
+	if round.Round != schema.ROUND_SINGLE || len(round.Columns) != 6 || len(round.Challenges) != 30 {
+		t.Fatalf("generated %d columns and %d challenges", len(round.Columns), len(round.Challenges))
+	}
+
+	titles := make(map[schema.CategoryName]bool)
+	for _, column := range round.Columns {
+		if titles[column.Name] {
+			t.Errorf("title %s appears twice", column.Name)
+		}
+		titles[column.Name] = true
+	}
+	history := 0
+	for _, column := range round.Columns {
+		if column.CategoryID%2 == 0 {
+			history++
+		}
+	}
+	if history < 4 {
+		t.Errorf("only %d history columns, expected at least 4", history)
+	}
+
+	for _, challenge := range round.Challenges {
+		id := uint64(challenge.ChallengeID)
+		if seen[challenge.ChallengeID] || id%100 == 6 {
+			t.Errorf("challenge %d should have been excluded", id)
+		}
+		if category := round.Columns[challenge.Column-1]; schema.CategoryID(id/100) != category.CategoryID {
+			t.Errorf("challenge %d in the column of category %d", id, category.CategoryID)
+		}
+	}
+
+	again, _ := schema.GenerateBoard(testPool(), schema.BoardConstraints{
+		Round:      schema.ROUND_SINGLE,
+		Themes:     map[schema.CategoryThemeEnum]uint{schema.CATEGORY_HISTORY_ROYALTY: 4},
+		Exclude:    seen,
+		MinQuality: schema.QUALITY_CORRECT,
+		Seed:       1984})
+	if !reflect.DeepEqual(round, again) {
+		t.Error("the same seed generated a different board")
+	}
+}
+
+// The zero constraints generate a board that can be played to the end.
+func TestGenerateBoardPlayable(t *testing.T) {
+	round, err := schema.GenerateBoard(testPool(), schema.BoardConstraints{})
+	if err != nil {
+		t.Fatalf("GenerateBoard() error = %v", err)
+	}
+	if round.Round != schema.ROUND_SINGLE || len(round.Challenges) != 30 {
+		t.Fatalf("generated %s with %d challenges", round.RoundName(), len(round.Challenges))
+	}
+	state, err := round.Replay()
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	for _, challenge := range round.Challenges {
+		if err := state.Select(schema.BoardSelection{BoardPosition: challenge.BoardPosition}); err != nil {
+			t.Errorf("Select(%s) error = %v", challenge.BoardPosition, err)
+		}
+	}
+	if !state.IsCleared() {
+		t.Errorf("board is not cleared, remaining %v", state.Remaining())
+	}
+}
+
+func TestGenerateBoardDifficulty(t *testing.T) {
+	round, err := schema.GenerateBoard(testPool(), schema.BoardConstraints{
+		Columns: 3, MinDifficulty: 1, MaxDifficulty: 4, Seed: 7})
+	if err != nil {
+		t.Fatalf("GenerateBoard() error = %v", err)
+	}
+	// The difficulty is 5-j%5 for the j-th challenge of the category.
+	difficulty := func(challenge schema.BoardChallenge) int {
+		return 5 - int(challenge.ChallengeID%100)%5
+	}
+	for i, challenge := range round.Challenges {
+		if d := difficulty(challenge); d < 1 || d > 4 {
+			t.Errorf("challenge %d has difficulty %d", challenge.ChallengeID, d)
+		}
+		if challenge.Index > 1 && difficulty(round.Challenges[i-1]) > difficulty(challenge) {
+			t.Errorf("challenge %d at index %d is easier than the one above it",
+				challenge.ChallengeID, challenge.Index)
+		}
+	}
+}
+
+func TestGenerateBoardErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints schema.BoardConstraints
+		err         error
+	}{
+		{"too few aired", schema.BoardConstraints{
+			Aired: schema.ShowDateRange{Until: date(2004, 12, 31)}}, schema.ErrBoardConstraints},
+		{"too many of a theme", schema.BoardConstraints{
+			Themes: map[schema.CategoryThemeEnum]uint{schema.CATEGORY_SCIENCE_NATURE: 6}}, schema.ErrBoardConstraints},
+		{"themes exceed columns", schema.BoardConstraints{Columns: 2,
+			Themes: map[schema.CategoryThemeEnum]uint{schema.CATEGORY_SCIENCE_NATURE: 3}}, schema.ErrBoardConstraints},
+		{"too few challenges", schema.BoardConstraints{MinDifficulty: 5}, schema.ErrBoardConstraints},
+		{"enough aired", schema.BoardConstraints{Columns: 3,
+			Aired: schema.ShowDateRange{Until: date(2004, 12, 31)}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := schema.GenerateBoard(testPool(), tt.constraints); !errors.Is(err, tt.err) {
+				t.Errorf("GenerateBoard() error = %v, expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestGenerateBoardQuality(t *testing.T) {
+	tests := []struct {
+		quality   schema.DataQualityEnum
+		byDefault bool // allowed with the zero MinQuality
+		ifCorrect bool // allowed with a MinQuality of QUALITY_CORRECT
+	}{
+		{schema.QUALITY_NEEDS_REVIEW, true, false},
+		{schema.QUALITY_ENTIRELY_INCORRECT, false, false},
+		{schema.QUALITY_RECENTLY_INCORRECT, false, false},
+		{schema.QUALITY_SUSPECTED_OUTDATED, false, false},
+		{schema.QUALITY_NEEDS_MINOR_CHANGE, true, false},
+		{schema.QUALITY_DISAGREEMENT, true, false},
+		{schema.QUALITY_CORRECT, true, true},
+		{schema.QUALITY_CONFIRMED_CORRECT, true, true},
+	}
+	if len(tests) != int(schema.MaxDataQualityEnum) {
+		t.Fatalf("%d qualities tested, expected all %d", len(tests), schema.MaxDataQualityEnum)
+	}
+	for _, tt := range tests {
+		t.Run(tt.quality.String(), func(t *testing.T) {
+			pool := testPool()[:1]
+			for i := range pool[0].Challenges {
+				pool[0].Challenges[i].Quality = tt.quality
+			}
+			for _, minimum := range []schema.DataQualityEnum{schema.QUALITY_NEEDS_REVIEW, schema.QUALITY_CORRECT} {
+				allowed := tt.byDefault
+				if minimum == schema.QUALITY_CORRECT {
+					allowed = tt.ifCorrect
+				}
+				_, err := schema.GenerateBoard(pool, schema.BoardConstraints{
+					Columns: 1, MinQuality: minimum})
+				if allowed != (err == nil) {
+					t.Errorf("GenerateBoard(MinQuality: %s) error = %v, expected allowed = %v",
+						minimum, err, allowed)
+				}
+			}
+		})
+	}
+}
@@ -1,3 +1,19 @@
 module github.com/kevindamm/q-party/schema
 
 go 1.23.4
+
+require (
+	cuelang.org/go v0.14.1
+	golang.org/x/text v0.27.0
+)
+
+require (
+	github.com/cockroachdb/apd/v3 v3.2.1 // indirect
+	github.com/emicklei/proto v1.14.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mitchellh/go-wordwrap v1.0.1 // indirect
+	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
+	github.com/protocolbuffers/txtpbfmt v0.0.0-20250627152318-f293424e46b5 // indirect
+	golang.org/x/net v0.42.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
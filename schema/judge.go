@@ -0,0 +1,395 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/judge.go
+
+package schema
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Compares a contestant's response to the acceptable answers of a challenge.
+// The zero value only accepts responses that are the same after normalizing,
+// see DefaultJudge for a more lenient one.
+type Judge struct {
+	// Accept the last name alone for answers of more than one word.
+	Surnames bool
+	// Accept one misspelled letter for every this many letters, if non-zero.
+	LettersPerEdit int
+	// Accept words that sound like the answer's, as for transcribed responses
+	// where speech recognition guesses at the spelling of names.
+	Phonetic bool
+}
+
+var DefaultJudge = Judge{Surnames: true, LettersPerEdit: 5}
+
+// Why a response was judged correct or not.
+type ReasonEnum uint8
+
+const (
+	REASON_NO_MATCH ReasonEnum = iota
+	REASON_EMPTY
+	REASON_EXACT
+	REASON_SPACING
+	REASON_SURNAME
+	REASON_SPELLING
+	REASON_PHONETIC
+	REASON_HOST
+	MaxReasonEnum
+)
+
+var reason_names = [MaxReasonEnum]string{
+	"does not match any acceptable answer",
+	"no response",
+	"matches an acceptable answer",
+	"matches except for spacing",
+	"matches the surname of an acceptable answer",
+	"matches except for spelling",
+	"sounds like an acceptable answer",
+	"judged by the host",
+}
+
+func (reason ReasonEnum) String() string {
+	if reason >= MaxReasonEnum {
+		return reason_names[REASON_NO_MATCH]
+	}
+	return reason_names[reason]
+}
+
+type Verdict struct {
+	Correct bool
+	// How confident the judge is in this verdict, from 0 to 1.
+	Confidence float64
+	Reason     ReasonEnum
+	// The acceptable answer that the response was closest to, if any.
+	Matched string
+}
+
+// The host has the final say, overriding the judge's verdict.
+func (verdict Verdict) Override(correct bool) Verdict {
+	verdict.Correct = correct
+	verdict.Confidence = 1
+	verdict.Reason = REASON_HOST
+	return verdict
+}
+
+// Judges the player's response to the challenge, also comparing how the words
+// sound if the response was transcribed from speech.
+func (judge Judge) JudgeResponse(challenge HostChallenge, response PlayerResponse) Verdict {
+	if response.Transcribed {
+		judge.Phonetic = true
+	}
+	return judge.Evaluate(challenge.Correct, response.Response)
+}
+
+// Judges the response against each of the acceptable answers, returning the
+// most favorable verdict.
+func (judge Judge) Evaluate(correct []string, response string) Verdict {
+	responses := responseCandidates(response)
+	if len(responses) == 0 {
+		return Verdict{Confidence: 1, Reason: REASON_EMPTY}
+	}
+
+	best := Verdict{Confidence: 1, Reason: REASON_NO_MATCH}
+	closest := 0.0
+	for _, answer := range correct {
+		expected := normalizeResponse(answer)
+		if len(expected) == 0 {
+			continue
+		}
+		for _, given := range responses {
+			verdict, similarity := judge.compare(expected, given)
+			verdict.Matched = answer
+			if verdict.Correct {
+				if !best.Correct || verdict.Confidence > best.Confidence {
+					best = verdict
+				}
+				continue
+			}
+			if !best.Correct && similarity > closest {
+				closest = similarity
+				best = Verdict{Confidence: 1 - similarity, Reason: REASON_NO_MATCH, Matched: answer}
+			}
+		}
+	}
+	return best
+}
+
+// Compares the normalized words of an answer and a response, returning the
+// verdict and (for an incorrect response) how similar they were.
+func (judge Judge) compare(expected, given []string) (Verdict, float64) {
+	answer, response := strings.Join(expected, " "), strings.Join(given, " ")
+	if answer == response {
+		return Verdict{Correct: true, Confidence: 1, Reason: REASON_EXACT}, 1
+	}
+	if strings.Join(expected, "") == strings.Join(given, "") {
+		return Verdict{Correct: true, Confidence: 0.95, Reason: REASON_SPACING}, 1
+	}
+
+	surname := expected[len(expected)-1]
+	if judge.Surnames && len(expected) > 1 && len(surname) > 2 && response == surname {
+		return Verdict{Correct: true, Confidence: 0.8, Reason: REASON_SURNAME}, 1
+	}
+
+	candidates := [][]string{expected}
+	if judge.Surnames && len(expected) > 1 {
+		candidates = append(candidates, expected[len(expected)-1:])
+	}
+	similarity := 0.0
+	for _, words := range candidates {
+		// Numbers must match exactly, a misspelled year is a different year.
+		candidate := strings.Join(words, " ")
+		if containsDigit(candidate) {
+			continue
+		}
+		length := len([]rune(candidate))
+		distance := editDistance(candidate, response)
+		// Partial matches (on the surname alone) are less certain.
+		partial := 0.0
+		if len(words) < len(expected) {
+			partial = 0.1
+		}
+
+		if judge.LettersPerEdit > 0 && distance <= length/judge.LettersPerEdit {
+			confidence := 0.9 - 0.1*float64(distance) - partial
+			return Verdict{Correct: true, Confidence: confidence, Reason: REASON_SPELLING}, 1
+		}
+		textual := 1 - float64(distance)/float64(max(length, len([]rune(response))))
+		if judge.Phonetic && textual >= minPhoneticSimilarity && wordsSoundAlike(words, given) {
+			confidence := 0.5 + 0.4*textual - partial
+			return Verdict{Correct: true, Confidence: confidence, Reason: REASON_PHONETIC}, 1
+		}
+		similarity = max(similarity, textual)
+	}
+	return Verdict{}, similarity
+}
+
+// Short words have too many neighbors that sound alike ("Mars" and "Moors",
+// "Iran" and "Oran"), so these must be spelled (nearly) correctly.  Longer words
+// must also be spelled somewhat alike, as a fraction of their letters.
+const (
+	minPhoneticLetters    = 5
+	minPhoneticSimilarity = 0.5
+)
+
+// True if each of the words sounds like the other's word in the same place.
+func wordsSoundAlike(expected, given []string) bool {
+	if len(expected) != len(given) {
+		return false
+	}
+	for i := range expected {
+		if expected[i] == given[i] {
+			continue
+		}
+		if min(len([]rune(expected[i])), len([]rune(given[i]))) < minPhoneticLetters ||
+			!SoundsAlike(expected[i], given[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the words of a response to judge, both without the question's preface
+// ("what is", "who are", ...) and as given, since the preface may instead be
+// part of the answer ("Who's Afraid of Virginia Woolf").  A response that is
+// only a preface has no words to judge.
+func responseCandidates(response string) [][]string {
+	words := normalizeResponse(response)
+	preface := 0
+	switch {
+	case len(words) > 1 && questionWords[words[0]] && questionVerbs[words[1]]:
+		preface = 2
+	case len(words) > 0 && questionContractions[words[0]]:
+		preface = 1
+	}
+	if preface == len(words) {
+		return nil
+	}
+	if preface == 0 {
+		return [][]string{words}
+	}
+	return [][]string{words[preface:], words}
+}
+
+// Reduces a response (or answer) to its essential words: lower case, without
+// diacritics, punctuation or articles, and with number words as digits.
+func normalizeResponse(text string) []string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r == '\'' || r == '’':
+		case r == '&':
+			builder.WriteString(" and ")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if folded, found := foldedLetters[r]; found {
+				builder.WriteString(folded)
+			} else {
+				builder.WriteRune(r)
+			}
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+	words := strings.Fields(builder.String())
+	kept := words[:0]
+	for _, word := range words {
+		if !articles[word] {
+			kept = append(kept, word)
+		}
+	}
+	return numberWords(kept)
+}
+
+var questionWords = map[string]bool{
+	"what": true, "who": true, "where": true, "when": true, "which": true,
+}
+
+// The question word and verb together, after apostrophes are removed.
+var questionContractions = map[string]bool{
+	"whats": true, "whos": true, "wheres": true, "whens": true,
+}
+
+var questionVerbs = map[string]bool{
+	"is": true, "are": true, "was": true, "were": true, "s": true,
+}
+
+var articles = map[string]bool{"a": true, "an": true, "the": true}
+
+// Letters that do not decompose into a base letter and a diacritic.
+var foldedLetters = map[rune]string{
+	'ø': "o", 'æ': "ae", 'œ': "oe", 'ß': "ss", 'ł': "l", 'đ': "d", 'þ': "th",
+}
+
+var numberValues = map[string]int{
+	"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11,
+	"twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15,
+	"sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+	"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50, "sixty": 60,
+	"seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+var numberScales = map[string]int{"hundred": 100, "thousand": 1000, "million": 1000000}
+
+var ordinals = map[string]string{
+	"first": "1st", "second": "2nd", "third": "3rd", "fourth": "4th",
+	"fifth": "5th", "sixth": "6th", "seventh": "7th", "eighth": "8th",
+	"ninth": "9th", "tenth": "10th",
+}
+
+// Replaces each run of number words with its digits, "twenty one" becomes "21"
+// and "one hundred and five" becomes "105".  Years are usually spoken as two
+// numbers, "nineteen eighty four" becomes "1984" rather than their sum.
+func numberWords(words []string) []string {
+	result := make([]string, 0, len(words))
+	prefix, total, current, counting := 0, 0, 0, false
+	flush := func() {
+		if counting {
+			number := total + current
+			if prefix != 0 {
+				number += prefix * 100
+			}
+			result = append(result, strconv.Itoa(number))
+			prefix, total, current, counting = 0, 0, 0, false
+		}
+	}
+	for i, word := range words {
+		if value, found := numberValues[word]; found {
+			if counting && !followsNumber(current, value) {
+				if prefix == 0 && total == 0 && current >= 10 && current < 100 {
+					// The first half of a year, as in "nineteen" of "nineteen ten".
+					prefix, current = current, value
+					continue
+				}
+				flush()
+			}
+			current += value
+			counting = true
+			continue
+		}
+		if scale, found := numberScales[word]; found && (counting || scale == 100) {
+			if current == 0 {
+				current = 1
+			}
+			if scale == 100 {
+				current *= scale
+			} else {
+				total += current * scale
+				current = 0
+			}
+			counting = true
+			continue
+		}
+		if word == "and" && counting && i+1 < len(words) {
+			if _, found := numberValues[words[i+1]]; found {
+				continue
+			}
+		}
+		flush()
+		if ordinal, found := ordinals[word]; found {
+			word = ordinal
+		}
+		result = append(result, word)
+	}
+	flush()
+	return result
+}
+
+// True if the value can be added to the number being counted, as "one" can be
+// after "twenty" but not after "nineteen" or another "one".
+func followsNumber(current, value int) bool {
+	tens := current % 100
+	if value < 10 {
+		return tens%10 == 0 && (tens == 0 || tens >= 20)
+	}
+	return tens == 0
+}
+
+func containsDigit(text string) bool {
+	return strings.IndexFunc(text, unicode.IsDigit) >= 0
+}
+
+// The Levenshtein distance between a and b, in runes.
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/judge_test.go
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func TestJudgeEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		correct  []string
+		response string
+		verdict  bool
+		reason   schema.ReasonEnum
+	}{
+		{"exact", []string{"Nile"}, "the Nile", true, schema.REASON_EXACT},
+		{"question preface", []string{"the Nile"}, "What is the Nile?", true, schema.REASON_EXACT},
+		{"contraction preface", []string{"Nile"}, "what's the nile", true, schema.REASON_EXACT},
+		{"plural preface", []string{"The Beatles"}, "Who are the Beatles", true, schema.REASON_EXACT},
+		{"punctuation", []string{"Rock 'n' Roll"}, "rock n roll!", true, schema.REASON_EXACT},
+		{"diacritics", []string{"Beyoncé"}, "beyonce", true, schema.REASON_EXACT},
+		{"folded letters", []string{"Søren Kierkegaard"}, "soren kierkegaard", true, schema.REASON_EXACT},
+		{"ampersand", []string{"Tom & Jerry"}, "Tom and Jerry", true, schema.REASON_EXACT},
+		{"number words", []string{"Catch-22"}, "catch twenty two", true, schema.REASON_EXACT},
+		{"large number words", []string{"1,105"}, "one thousand one hundred and five", true, schema.REASON_SPACING},
+		{"year words", []string{"1984"}, "nineteen eighty four", true, schema.REASON_EXACT},
+		{"hyphenated year words", []string{"1776"}, "seventeen seventy-six", true, schema.REASON_EXACT},
+		{"early year words", []string{"1905"}, "nineteen five", true, schema.REASON_EXACT},
+		{"hundreds year words", []string{"1905"}, "nineteen hundred and five", true, schema.REASON_EXACT},
+		{"separate number words", []string{"1, 2, 3"}, "one two three", true, schema.REASON_EXACT},
+		{"ordinals", []string{"the 1st Amendment"}, "First Amendment", true, schema.REASON_EXACT},
+		{"spacing", []string{"Spider-Man"}, "spiderman", true, schema.REASON_SPACING},
+		{"surname", []string{"Abraham Lincoln"}, "Who is Lincoln?", true, schema.REASON_SURNAME},
+		{"misspelled", []string{"Mississippi"}, "missisipi", true, schema.REASON_SPELLING},
+		{"misspelled surname", []string{"Dwight Eisenhower"}, "eisenhowser", true, schema.REASON_SPELLING},
+		{"too misspelled", []string{"Mississippi"}, "misery", false, schema.REASON_NO_MATCH},
+		{"short answers are exact", []string{"Oslo"}, "Olso", false, schema.REASON_NO_MATCH},
+		{"wrong year", []string{"1984"}, "1985", false, schema.REASON_NO_MATCH},
+		{"first name only", []string{"Abraham Lincoln"}, "Abraham", false, schema.REASON_NO_MATCH},
+		{"any acceptable answer", []string{"Burma", "Myanmar"}, "myanmar", true, schema.REASON_EXACT},
+		{"empty", []string{"Nile"}, "What is...", false, schema.REASON_EMPTY},
+		{"question word without verb", []string{"When Harry Met Sally"}, "When Harry Met Sally", true, schema.REASON_EXACT},
+		{"bare question word", []string{"The Who"}, "Who", true, schema.REASON_EXACT},
+		{"contraction in answer", []string{"Who's Afraid of Virginia Woolf"}, "Who's Afraid of Virginia Woolf", true, schema.REASON_EXACT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := schema.DefaultJudge.Evaluate(tt.correct, tt.response)
+			if verdict.Correct != tt.verdict || verdict.Reason != tt.reason {
+				t.Errorf("Evaluate(%q) = %v (%s), expected %v (%s)", tt.response,
+					verdict.Correct, verdict.Reason, tt.verdict, tt.reason)
+			}
+			if verdict.Confidence <= 0 || verdict.Confidence > 1 {
+				t.Errorf("confidence %f is out of range", verdict.Confidence)
+			}
+		})
+	}
+}
+
+func TestJudgeStrict(t *testing.T) {
+	var strict schema.Judge
+	if verdict := strict.Evaluate([]string{"Abraham Lincoln"}, "Lincoln"); verdict.Correct {
+		t.Errorf("strict judge accepted a surname: %+v", verdict)
+	}
+	if verdict := strict.Evaluate([]string{"Mississippi"}, "missisipi"); verdict.Correct {
+		t.Errorf("strict judge accepted a misspelling: %+v", verdict)
+	}
+}
+
+func TestJudgeConfidenceAndOverride(t *testing.T) {
+	exact := schema.DefaultJudge.Evaluate([]string{"Mississippi"}, "Mississippi")
+	misspelled := schema.DefaultJudge.Evaluate([]string{"Mississippi"}, "Missisippi")
+	if !misspelled.Correct || misspelled.Confidence >= exact.Confidence {
+		t.Errorf("misspelled %+v is not less confident than exact %+v", misspelled, exact)
+	}
+	near := schema.DefaultJudge.Evaluate([]string{"Mississippi"}, "Missouri")
+	far := schema.DefaultJudge.Evaluate([]string{"Mississippi"}, "Hudson")
+	if near.Correct || far.Correct || near.Confidence >= far.Confidence {
+		t.Errorf("near miss %+v should be less certain than %+v", near, far)
+	}
+	if near.Matched != "Mississippi" {
+		t.Errorf("closest answer = %q", near.Matched)
+	}
+
+	challenge := schema.HostChallenge{Correct: []string{"Mississippi"}}
+	response := schema.PlayerResponse{Response: "the Big Muddy"}
+	verdict := schema.DefaultJudge.JudgeResponse(challenge, response).Override(true)
+	if !verdict.Correct || verdict.Reason != schema.REASON_HOST || verdict.Confidence != 1 {
+		t.Errorf("Override() = %+v", verdict)
+	}
+}
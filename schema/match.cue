@@ -36,18 +36,22 @@ package schema
 #MatchIndex: [#MatchNumber]: #MatchMetadata
 
 // Identifiers and statistics for each episode.
-#MatchMetadata: #MatchID & {
+#MatchMetadata: {
+  #MatchID
   aired?: #ShowDate
   taped?: #ShowDate
 
   contestants?: [...#ContestantID]
   media?: [...#MediaRef]
   comments?: string
-  ...
 }
 // Additional statistics for the match, with match metadata included.
-#MatchStats: #MatchMetadata & {
+#MatchStats: {
+  #MatchMetadata
   single_count?: int
   double_count?: int
+  challenge_count?: int
+  category_count?: int
   triple_stumpers?: [...#BoardPosition]
+  stumped_value?: #Value
 }
@@ -23,10 +23,14 @@
 package schema
 
 import (
+	"bytes"
+	"cmp"
 	_ "embed"
+	"slices"
+	"strconv"
 )
 
-// go:embed match.cue
+//go:embed match.cue
 var schemaMatches string
 
 // A match identifier refers to the unique identifier of the ?-Party database.
@@ -59,46 +63,61 @@ type MatchMetadata struct {
 	Comments    string         `json:"comments,omitempty"`
 }
 
+// The episodes of a season (or of the whole archive), see MatchCatalog for
+// querying them.
 type MatchIndex map[MatchNumber]*MatchMetadata
 
-// Will add (and possibly overwrite) the values from metadata into the mapping.
-func (episodes MatchIndex) Update(metadata MatchMetadata) {
-	match := metadata.MatchNumber
-	episode, exists := episodes[match]
-	if !exists {
-		episodes[match] = &metadata
-		return
-	}
-
-	if len(metadata.SeasonSlug) > 0 {
-		episode.SeasonSlug = metadata.SeasonSlug
-	}
-	if len(metadata.ShowTitle) > 0 {
-		episode.ShowTitle = metadata.ShowTitle
-	}
+// Merges the values from metadata into the mapping using DefaultMergePolicy,
+// returning the fields where the existing and incoming values disagreed.
+func (episodes MatchIndex) Update(metadata MatchMetadata) []MergeConflict {
+	return episodes.Merge(metadata, DefaultMergePolicy)
+}
 
-	if metadata.AiredDate.String() != "" {
-		episode.AiredDate = metadata.AiredDate
+// The default encoding sorts integer keys by their string representation,
+// this orders the matches numerically instead.
+func (episodes MatchIndex) MarshalJSON() ([]byte, error) {
+	if episodes == nil {
+		return []byte("null"), nil
 	}
-	if metadata.TapedDate.String() != "" {
-		episode.TapedDate = metadata.AiredDate
+	keys := make([]MatchNumber, 0, len(episodes))
+	for match := range episodes {
+		keys = append(keys, match)
 	}
+	slices.Sort(keys)
 
-	// Sequence types are added to, not replaced with.  TODO merge lists
-	if len(metadata.Contestants) > 0 {
-		episode.Contestants = append(episode.Contestants, metadata.Contestants...)
-	}
-	if len(metadata.Media) > 0 {
-		episode.Media = append(episode.Media, metadata.Media...)
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, match := range keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteByte('"')
+		buffer.WriteString(strconv.FormatUint(uint64(match), 10))
+		buffer.WriteString(`":`)
+		encoded, err := marshalCompact(episodes[match])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encoded)
 	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
 
-	if len(metadata.Comments) > 0 {
-		if len(episode.Comments) == 0 {
-			episode.Comments = metadata.Comments
-		} else {
-			episode.Comments = episode.Comments + "\n\n" + metadata.Comments
+// True if a aired before b, falling back to the match number when the aired
+// dates are the same or unknown.
+func airedBefore(a, b *MatchMetadata) bool {
+	switch {
+	case a.AiredDate != nil && b.AiredDate != nil:
+		if order := a.AiredDate.Compare(b.AiredDate); order != 0 {
+			return order < 0
 		}
+	case a.AiredDate != nil:
+		return true
+	case b.AiredDate != nil:
+		return false
 	}
+	return a.MatchNumber < b.MatchNumber
 }
 
 type MatchStats struct {
@@ -106,5 +125,55 @@ type MatchStats struct {
 	SingleCount   int `json:"single_count,omitempty"`
 	DoubleCount   int `json:"double_count,omitempty"`
 
+	// Totals over all rounds, including Final and any tiebreakers.
+	ChallengeCount int `json:"challenge_count,omitempty"`
+	CategoryCount  int `json:"category_count,omitempty"`
+
 	TripleStumpers []BoardPosition `json:"triple_stumpers,omitempty"`
+	// The total value of the triple stumpers, as aired.
+	StumpedValue Value `json:"stumped_value,omitempty"`
+}
+
+// Counts the clues revealed in each round of the match's boards, and the board
+// positions where none of the contestants responded correctly.  The triple
+// stumpers are ordered by round (in the order of boards) and then by position,
+// their values are those of the ClassicValues ladder in the era the match aired
+// (or the latest era, if its aired date is not known).
+func ComputeMatchStats(metadata MatchMetadata, boards []BoardState) MatchStats {
+	stats := MatchStats{MatchMetadata: metadata}
+	for _, board := range boards {
+		// Whether any contestant was correct, for each position revealed.
+		revealed := make(map[BoardPosition]bool)
+		for _, outcome := range board.History {
+			revealed[outcome.BoardPosition] =
+				revealed[outcome.BoardPosition] || outcome.Correct
+		}
+
+		switch board.Round {
+		case ROUND_SINGLE:
+			stats.SingleCount += len(revealed)
+		case ROUND_DOUBLE:
+			stats.DoubleCount += len(revealed)
+		}
+		stats.ChallengeCount += len(revealed)
+		stats.CategoryCount += len(board.Columns)
+
+		var stumpers []BoardPosition
+		for position, correct := range revealed {
+			if !correct {
+				stumpers = append(stumpers, position)
+			}
+		}
+		slices.SortFunc(stumpers, func(a, b BoardPosition) int {
+			if a.Column != b.Column {
+				return cmp.Compare(a.Column, b.Column)
+			}
+			return cmp.Compare(a.Index, b.Index)
+		})
+		stats.TripleStumpers = append(stats.TripleStumpers, stumpers...)
+		for _, position := range stumpers {
+			stats.StumpedValue += ClassicValues.valueAired(board.Round, position.Index, metadata.AiredDate)
+		}
+	}
+	return stats
 }
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/match_test.go
+
+package schema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func outcome(column, index uint, correct bool) schema.SelectionOutcome {
+	return schema.SelectionOutcome{
+		BoardSelection: schema.BoardSelection{
+			BoardPosition: schema.BoardPosition{Column: column, Index: index}},
+		Correct: correct}
+}
+
+func playedBoard(round schema.RoundEnum, columns int, history ...schema.SelectionOutcome) schema.BoardState {
+	board := schema.BoardState{History: history}
+	board.Round = round
+	board.Columns = make([]schema.CategoryMetadata, columns)
+	return board
+}
+
+func TestComputeMatchStats(t *testing.T) {
+	metadata := schema.MatchMetadata{MatchID: schema.MatchID{MatchNumber: 8000}}
+	boards := []schema.BoardState{
+		playedBoard(schema.ROUND_SINGLE, 6,
+			outcome(1, 1, true),
+			outcome(3, 2, false), // stumped, after two incorrect responses
+			outcome(3, 2, false),
+			outcome(2, 1, false), // rebound
+			outcome(2, 1, true),
+			outcome(1, 5, false)), // stumped without any other response
+		playedBoard(schema.ROUND_DOUBLE, 6,
+			outcome(6, 1, true),
+			outcome(4, 4, false),
+			outcome(4, 4, false),
+			outcome(4, 4, false)),
+		playedBoard(schema.ROUND_FINAL, 1,
+			outcome(1, 1, false),
+			outcome(1, 1, true),
+			outcome(1, 1, false)),
+	}
+
+	stats := schema.ComputeMatchStats(metadata, boards)
+	if stats.MatchNumber != 8000 {
+		t.Errorf("match number = %d", stats.MatchNumber)
+	}
+	if stats.SingleCount != 4 || stats.DoubleCount != 2 {
+		t.Errorf("single, double counts = %d, %d; expected 4, 2",
+			stats.SingleCount, stats.DoubleCount)
+	}
+	if stats.ChallengeCount != 7 || stats.CategoryCount != 13 {
+		t.Errorf("challenge, category counts = %d, %d; expected 7, 13",
+			stats.ChallengeCount, stats.CategoryCount)
+	}
+	stumpers := []schema.BoardPosition{{Column: 1, Index: 5}, {Column: 3, Index: 2}, {Column: 4, Index: 4}}
+	if stats.StumpedValue != 1000+400+1600 {
+		t.Errorf("stumped value = %d, expected 3000", stats.StumpedValue)
+	}
+	if !reflect.DeepEqual(stats.TripleStumpers, stumpers) {
+		t.Errorf("triple stumpers = %v\nexpected %v", stats.TripleStumpers, stumpers)
+	}
+
+	metadata.AiredDate = date(1995, 3, 14)
+	if stats := schema.ComputeMatchStats(metadata, boards); stats.StumpedValue != 500+200+800 {
+		t.Errorf("stumped value before 2001 = %d, expected 1500", stats.StumpedValue)
+	}
+
+	season := schema.SeasonMetadata{ChallengeCount: 60, CategoryCount: 13, TripStumpCount: 2}
+	season.AddMatchStats(stats)
+	season.AddMatchStats(schema.ComputeMatchStats(metadata, nil))
+	if season.ChallengeCount != 67 || season.CategoryCount != 26 || season.TripStumpCount != 5 {
+		t.Errorf("season totals = %d, %d, %d", season.ChallengeCount,
+			season.CategoryCount, season.TripStumpCount)
+	}
+}
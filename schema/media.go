@@ -0,0 +1,533 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/media.go
+
+package schema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"math/bits"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMediaType     = errors.New("unsupported media type")
+	ErrMediaURL      = errors.New("media URL is not a relative path")
+	ErrMediaMismatch = errors.New("media content does not match its type")
+	ErrMediaCorrupt  = errors.New("media content is malformed")
+)
+
+// The name used by the `filetype` column of the MediaClue table, or "" if the
+// type is not supported.
+func (mime MimeType) Filetype() string {
+	return mime_filetypes[mime]
+}
+
+// The media type for a name from the `filetype` column of the MediaClue table.
+func ParseFiletype(filetype string) (MimeType, error) {
+	for mime, name := range mime_filetypes {
+		if strings.EqualFold(name, filetype) {
+			return mime, nil
+		}
+	}
+	return "", fmt.Errorf("%w: filetype %q", ErrMediaType, filetype)
+}
+
+// The media type conventionally used for files with the path's extension.
+func MimeTypeByExtension(filepath string) (MimeType, error) {
+	switch ext := strings.ToLower(path.Ext(filepath)); ext {
+	case ".jpg", ".jpeg":
+		return MediaImageJPG, nil
+	case ".png":
+		return MediaImagePNG, nil
+	case ".svg":
+		return MediaImageSVG, nil
+	case ".mp3":
+		return MediaAudioMP3, nil
+	case ".mp4", ".m4v":
+		return MediaVideoMP4, nil
+	case ".mov", ".qt":
+		return MediaVideoMOV, nil
+	default:
+		return "", fmt.Errorf("%w: extension %q", ErrMediaType, ext)
+	}
+}
+
+// Checks that the media has a supported type (if one is given) and that its
+// URL is a path relative to the media's base URL, without any `..` elements.
+func (ref MediaRef) Validate() error {
+	if ref.MimeType != "" && ref.MimeType.Filetype() == "" {
+		return fmt.Errorf("%w: %q", ErrMediaType, ref.MimeType)
+	}
+	if !fs.ValidPath(ref.MediaURL) || ref.MediaURL == "." ||
+		strings.ContainsAny(ref.MediaURL, ":?#\\") {
+		return fmt.Errorf("%w: %q", ErrMediaURL, ref.MediaURL)
+	}
+	return nil
+}
+
+// Resolves the media's URL relative to the base URL that media are served from.
+func (ref MediaRef) Resolve(base *url.URL) (*url.URL, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+	resolved := *base
+	resolved.Path = strings.TrimSuffix(base.Path, "/") + "/" + ref.MediaURL
+	resolved.RawPath = ""
+	resolved.RawQuery = ""
+	resolved.Fragment = ""
+	return &resolved, nil
+}
+
+// What the content of a media file was found to be.  Width and height are in
+// pixels, for images and video; duration is for audio and video.  Each is zero
+// if the file does not declare it.
+type MediaInfo struct {
+	MimeType
+	Size          int64
+	Width, Height uint
+	Duration      time.Duration
+}
+
+// Validates the media reference and sniffs the content of its file within fsys
+// (e.g. os.DirFS of the media's base directory).  Returns ErrMediaMismatch if
+// the content is of a different type than the one declared, along with what it
+// was found to be.
+func CheckMedia(fsys fs.FS, ref MediaRef) (MediaInfo, error) {
+	if err := ref.Validate(); err != nil {
+		return MediaInfo{}, err
+	}
+	file, err := fsys.Open(ref.MediaURL)
+	if err != nil {
+		return MediaInfo{}, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return MediaInfo{}, err
+	}
+
+	reader, ok := file.(io.ReaderAt)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return MediaInfo{}, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	info, err := SniffMedia(reader, stat.Size())
+	if err != nil {
+		return info, fmt.Errorf("%s: %w", ref.MediaURL, err)
+	}
+	if ref.MimeType != "" && ref.MimeType != info.MimeType {
+		return info, fmt.Errorf("%w: %s is %s, not %s",
+			ErrMediaMismatch, ref.MediaURL, info.MimeType, ref.MimeType)
+	}
+	return info, nil
+}
+
+// Determines the type of the media from its content (not its name) and reads
+// its dimensions and duration from the headers.
+func SniffMedia(reader io.ReaderAt, size int64) (MediaInfo, error) {
+	header := make([]byte, min(size, 512))
+	if _, err := reader.ReadAt(header, 0); err != nil && err != io.EOF {
+		return MediaInfo{}, err
+	}
+	sniffed := mediaSniffer{reader, size}
+	info := MediaInfo{Size: size}
+	var err error
+	switch {
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		info.MimeType = MediaImagePNG
+		err = sniffed.png(&info)
+	case bytes.HasPrefix(header, []byte{0xFF, 0xD8, 0xFF}):
+		info.MimeType = MediaImageJPG
+		err = sniffed.jpeg(&info)
+	case len(header) >= 8 && isMovieBox(string(header[4:8])):
+		err = sniffed.movie(&info)
+	case bytes.HasPrefix(header, []byte("ID3")) || isAudioFrame(header):
+		info.MimeType = MediaAudioMP3
+		err = sniffed.mp3(&info)
+	case isSVG(io.NewSectionReader(reader, 0, size)):
+		info.MimeType = MediaImageSVG
+		err = sniffed.svg(&info)
+	default:
+		return info, ErrMediaType
+	}
+	return info, err
+}
+
+type mediaSniffer struct {
+	reader io.ReaderAt
+	size   int64
+}
+
+// Reads n bytes at the offset, or returns ErrMediaCorrupt if the file ends.
+func (sniffed mediaSniffer) read(offset int64, n int) ([]byte, error) {
+	if offset < 0 || n < 0 || offset+int64(n) > sniffed.size {
+		return nil, fmt.Errorf("%w: truncated at %d", ErrMediaCorrupt, offset)
+	}
+	data := make([]byte, n)
+	if _, err := sniffed.reader.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (sniffed mediaSniffer) png(info *MediaInfo) error {
+	ihdr, err := sniffed.read(8, 16)
+	if err != nil {
+		return err
+	}
+	if string(ihdr[4:8]) != "IHDR" {
+		return fmt.Errorf("%w: PNG does not start with IHDR", ErrMediaCorrupt)
+	}
+	info.Width = uint(binary.BigEndian.Uint32(ihdr[8:]))
+	info.Height = uint(binary.BigEndian.Uint32(ihdr[12:]))
+	return nil
+}
+
+func (sniffed mediaSniffer) jpeg(info *MediaInfo) error {
+	for offset := int64(2); ; {
+		marker, err := sniffed.read(offset, 4)
+		if err != nil {
+			return err
+		}
+		if marker[0] != 0xFF {
+			return fmt.Errorf("%w: JPEG marker expected at %d", ErrMediaCorrupt, offset)
+		}
+		switch code := marker[1]; {
+		case code == 0xFF:
+			// Markers may be preceded by any number of fill bytes.
+			offset++
+			continue
+		case code == 0x01 || code >= 0xD0 && code <= 0xD7:
+			offset += 2
+			continue
+		case code == 0xDA || code == 0xD9:
+			return fmt.Errorf("%w: JPEG has no frame header", ErrMediaCorrupt)
+		case code >= 0xC0 && code <= 0xCF && code != 0xC4 && code != 0xC8 && code != 0xCC:
+			frame, err := sniffed.read(offset+5, 4)
+			if err != nil {
+				return err
+			}
+			info.Height = uint(binary.BigEndian.Uint16(frame))
+			info.Width = uint(binary.BigEndian.Uint16(frame[2:]))
+			return nil
+		}
+		offset += 2 + int64(binary.BigEndian.Uint16(marker[2:]))
+	}
+}
+
+// True if the first element of the document is <svg>.  The prolog (comments,
+// DOCTYPE, etc.) before it may be of any length.
+func isSVG(document io.Reader) bool {
+	decoder := xml.NewDecoder(document)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			return token.Name.Local == "svg"
+		case xml.CharData:
+			if len(bytes.TrimSpace(token)) > 0 {
+				return false
+			}
+		}
+	}
+}
+
+func (sniffed mediaSniffer) svg(info *MediaInfo) error {
+	data, err := sniffed.read(0, int(sniffed.size))
+	if err != nil {
+		return err
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMediaCorrupt, err)
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var viewBox []string
+		for _, attr := range element.Attr {
+			switch attr.Name.Local {
+			case "width":
+				info.Width = svgLength(attr.Value)
+			case "height":
+				info.Height = svgLength(attr.Value)
+			case "viewBox":
+				viewBox = strings.Fields(strings.ReplaceAll(attr.Value, ",", " "))
+			}
+		}
+		if len(viewBox) == 4 {
+			if info.Width == 0 {
+				info.Width = svgLength(viewBox[2])
+			}
+			if info.Height == 0 {
+				info.Height = svgLength(viewBox[3])
+			}
+		}
+		// The rest of the document is not checked, it is well-formed enough.
+		return nil
+	}
+}
+
+// The length in pixels, or zero if it is relative (such as a percentage).
+func svgLength(length string) uint {
+	length = strings.TrimSuffix(strings.TrimSpace(length), "px")
+	value, err := strconv.ParseFloat(length, 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return uint(value + 0.5)
+}
+
+func isAudioFrame(header []byte) bool {
+	_, ok := parseAudioFrame(header)
+	return ok
+}
+
+type audioFrame struct {
+	bitrate    int // bits per second
+	sampleRate int
+	samples    int // per frame
+	sideInfo   int // bytes following the frame header
+	length     int // bytes, including the header
+}
+
+var (
+	mpeg1Bitrates = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mpeg2Bitrates = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+	sampleRates   = [4][3]int{
+		{11025, 12000, 8000},  // MPEG 2.5
+		{},                    // reserved
+		{22050, 24000, 16000}, // MPEG 2
+		{44100, 48000, 32000}, // MPEG 1
+	}
+)
+
+// Parses the header of an MPEG audio layer III frame.
+func parseAudioFrame(header []byte) (audioFrame, bool) {
+	if len(header) < 4 || header[0] != 0xFF || header[1]&0xE0 != 0xE0 {
+		return audioFrame{}, false
+	}
+	version := header[1] >> 3 & 3
+	layer := header[1] >> 1 & 3
+	bitrateIndex := header[2] >> 4
+	rateIndex := header[2] >> 2 & 3
+	if version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return audioFrame{}, false
+	}
+	mono := header[3]>>6 == 3
+	frame := audioFrame{sampleRate: sampleRates[version][rateIndex]}
+	if version == 3 {
+		frame.bitrate = mpeg1Bitrates[bitrateIndex] * 1000
+		frame.samples = 1152
+		frame.sideInfo = 32
+		if mono {
+			frame.sideInfo = 17
+		}
+	} else {
+		frame.bitrate = mpeg2Bitrates[bitrateIndex] * 1000
+		frame.samples = 576
+		frame.sideInfo = 17
+		if mono {
+			frame.sideInfo = 9
+		}
+	}
+	padding := int(header[2] >> 1 & 1)
+	frame.length = frame.samples/8*frame.bitrate/frame.sampleRate + padding
+	return frame, true
+}
+
+func (sniffed mediaSniffer) mp3(info *MediaInfo) error {
+	start := int64(0)
+	if id3, err := sniffed.read(0, 10); err == nil && string(id3[:3]) == "ID3" {
+		start = 10 + (int64(id3[6]&0x7F)<<21 | int64(id3[7]&0x7F)<<14 |
+			int64(id3[8]&0x7F)<<7 | int64(id3[9]&0x7F))
+		if id3[5]&0x10 != 0 {
+			start += 10 // footer
+		}
+	}
+	header, err := sniffed.read(start, 4)
+	if err != nil {
+		return err
+	}
+	frame, ok := parseAudioFrame(header)
+	if !ok {
+		return fmt.Errorf("%w: no MPEG audio frame at %d", ErrMediaCorrupt, start)
+	}
+
+	// A Xing (VBR) or Info (CBR) header in the first frame gives the frame count.
+	if xing, err := sniffed.read(start+4+int64(frame.sideInfo), 12); err == nil {
+		tag := string(xing[:4])
+		if (tag == "Xing" || tag == "Info") && xing[7]&1 != 0 {
+			frames := binary.BigEndian.Uint32(xing[8:])
+			info.Duration, err = mediaDuration(
+				uint64(frames)*uint64(frame.samples), uint64(frame.sampleRate))
+			return err
+		}
+	}
+	// Otherwise, assume a constant bitrate throughout.
+	end := sniffed.size
+	if tag, err := sniffed.read(end-128, 3); err == nil && string(tag) == "TAG" {
+		end -= 128
+	}
+	// The bitrates are all multiples of 8, this is the number of bytes per second.
+	info.Duration, err = mediaDuration(uint64(end-start), uint64(frame.bitrate/8))
+	return err
+}
+
+// The duration of count units of 1/rate seconds each, as in the sample count and
+// sample rate of audio.  Durations too long to represent are corrupt.
+func mediaDuration(count, rate uint64) (time.Duration, error) {
+	seconds, remainder := count/rate, count%rate
+	if seconds > math.MaxInt64/uint64(time.Second) {
+		return 0, fmt.Errorf("%w: duration of %d seconds", ErrMediaCorrupt, seconds)
+	}
+	// The remainder is less than the rate, so the quotient is less than a second.
+	high, low := bits.Mul64(remainder, uint64(time.Second))
+	fraction, _ := bits.Div64(high, low, rate)
+	return time.Duration(seconds)*time.Second + time.Duration(fraction), nil
+}
+
+func isMovieBox(kind string) bool {
+	switch kind {
+	case "ftyp", "moov", "mdat", "wide", "free", "skip", "pnot":
+		return true
+	}
+	return false
+}
+
+// Walks the boxes (atoms) of an ISO media file, MP4 or QuickTime, calling visit
+// with the type and the content's offset and size of each.
+func (sniffed mediaSniffer) boxes(offset, end int64, visit func(kind string, offset, size int64) error) error {
+	for offset+8 <= end {
+		header, err := sniffed.read(offset, 8)
+		if err != nil {
+			return err
+		}
+		size, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			large, err := sniffed.read(offset+8, 8)
+			if err != nil {
+				return err
+			}
+			size, headerSize = int64(binary.BigEndian.Uint64(large)), 16
+		}
+		if size < headerSize || offset+size > end {
+			return fmt.Errorf("%w: box %q overruns its container", ErrMediaCorrupt, header[4:])
+		}
+		if err := visit(string(header[4:]), offset+headerSize, size-headerSize); err != nil {
+			return err
+		}
+		offset += size
+	}
+	return nil
+}
+
+// Sniffs MP4 or, lacking an ftyp box with another brand, QuickTime.
+func (sniffed mediaSniffer) movie(info *MediaInfo) error {
+	info.MimeType = MediaVideoMOV
+	var moov bool
+	err := sniffed.boxes(0, sniffed.size, func(kind string, offset, size int64) error {
+		switch kind {
+		case "ftyp":
+			brand, err := sniffed.read(offset, 4)
+			if err != nil {
+				return err
+			}
+			if string(brand) != "qt  " {
+				info.MimeType = MediaVideoMP4
+			}
+		case "moov":
+			moov = true
+			return sniffed.moov(info, offset, offset+size)
+		}
+		return nil
+	})
+	if err == nil && !moov {
+		err = fmt.Errorf("%w: no movie header", ErrMediaCorrupt)
+	}
+	return err
+}
+
+func (sniffed mediaSniffer) moov(info *MediaInfo, offset, end int64) error {
+	return sniffed.boxes(offset, end, func(kind string, offset, size int64) error {
+		switch kind {
+		case "mvhd":
+			data, err := sniffed.read(offset, int(min(size, 32)))
+			if err != nil {
+				return err
+			}
+			var timescale, duration uint64
+			if data[0] == 1 && len(data) >= 32 {
+				timescale = uint64(binary.BigEndian.Uint32(data[20:]))
+				duration = binary.BigEndian.Uint64(data[24:])
+			} else if len(data) >= 20 {
+				timescale = uint64(binary.BigEndian.Uint32(data[12:]))
+				duration = uint64(binary.BigEndian.Uint32(data[16:]))
+			}
+			if timescale != 0 {
+				info.Duration, err = mediaDuration(duration, timescale)
+				return err
+			}
+		case "trak":
+			if info.Width != 0 {
+				return nil
+			}
+			return sniffed.boxes(offset, offset+size, func(kind string, offset, size int64) error {
+				if kind != "tkhd" {
+					return nil
+				}
+				// The dimensions, in 16.16 fixed point, end the track header.
+				data, err := sniffed.read(offset+size-8, 8)
+				if err != nil {
+					return err
+				}
+				info.Width = uint(binary.BigEndian.Uint32(data) >> 16)
+				info.Height = uint(binary.BigEndian.Uint32(data[4:]) >> 16)
+				return nil
+			})
+		}
+		return nil
+	})
+}
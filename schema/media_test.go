@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/media_test.go
+
+package schema_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func TestFiletype(t *testing.T) {
+	tests := []struct {
+		mime     schema.MimeType
+		filetype string
+	}{
+		{schema.MediaImageJPG, "JPEG"},
+		{schema.MediaImagePNG, "PNG"},
+		{schema.MediaImageSVG, "SVG"},
+		{schema.MediaAudioMP3, "MP3"},
+		{schema.MediaVideoMP4, "MP4"},
+		{schema.MediaVideoMOV, "MOV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filetype, func(t *testing.T) {
+			if got := tt.mime.Filetype(); got != tt.filetype {
+				t.Errorf("Filetype() = %q, want %q", got, tt.filetype)
+			}
+			mime, err := schema.ParseFiletype(tt.filetype)
+			if err != nil || mime != tt.mime {
+				t.Errorf("ParseFiletype() = %q, %v; want %q", mime, err, tt.mime)
+			}
+		})
+	}
+	if _, err := schema.ParseFiletype("GIF"); !errors.Is(err, schema.ErrMediaType) {
+		t.Errorf("expected ErrMediaType for GIF, got %v", err)
+	}
+	if mime, err := schema.MimeTypeByExtension("clues/Tower.JPEG"); mime != schema.MediaImageJPG {
+		t.Errorf("MimeTypeByExtension() = %q, %v", mime, err)
+	}
+}
+
+func TestMediaRefValidate(t *testing.T) {
+	tests := []struct {
+		ref schema.MediaRef
+		err error
+	}{
+		{schema.MediaRef{MimeType: schema.MediaImagePNG, MediaURL: "img/a.png"}, nil},
+		{schema.MediaRef{MediaURL: "a.mp3"}, nil},
+		{schema.MediaRef{MimeType: "image/gif", MediaURL: "a.gif"}, schema.ErrMediaType},
+		{schema.MediaRef{MediaURL: "/etc/passwd"}, schema.ErrMediaURL},
+		{schema.MediaRef{MediaURL: "img/../../a.png"}, schema.ErrMediaURL},
+		{schema.MediaRef{MediaURL: "https://example.com/a.png"}, schema.ErrMediaURL},
+		{schema.MediaRef{MediaURL: ""}, schema.ErrMediaURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref.MediaURL, func(t *testing.T) {
+			if err := tt.ref.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func encodedImage(t *testing.T, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := encode(&buffer, image.NewGray(image.Rect(0, 0, 64, 48))); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// A constant bitrate MP3 of 128kbps at 44.1kHz, or with a Xing header if frames
+// is not zero.
+func audioFrames(count int, frames uint32) []byte {
+	var data []byte
+	for i := range count {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x44})
+		if i == 0 && frames != 0 {
+			copy(frame[36:], "Xing\x00\x00\x00\x01")
+			binary.BigEndian.PutUint32(frame[44:], frames)
+		}
+		data = append(data, frame...)
+	}
+	return data
+}
+
+func box(kind string, content ...[]byte) []byte {
+	data := binary.BigEndian.AppendUint32(nil, 0)
+	data = append(data, kind...)
+	for _, part := range content {
+		data = append(data, part...)
+	}
+	binary.BigEndian.PutUint32(data, uint32(len(data)))
+	return data
+}
+
+func movie(brand string) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 600)
+	binary.BigEndian.PutUint32(mvhd[16:], 4500)
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], 640<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], 360<<16)
+	return append(box("ftyp", []byte(brand), make([]byte, 4)),
+		box("moov", box("mvhd", mvhd), box("trak", box("tkhd", tkhd)))...)
+}
+
+func TestSniffMedia(t *testing.T) {
+	pngData := encodedImage(t, func(buffer *bytes.Buffer, img image.Image) error {
+		return png.Encode(buffer, img)
+	})
+	jpegData := encodedImage(t, func(buffer *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(buffer, img, nil)
+	})
+	tagged := append([]byte("ID3\x04\x00\x00\x00\x00\x00\x0A"), make([]byte, 10)...)
+	tagged = append(tagged, audioFrames(10, 0)...)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		mime     schema.MimeType
+		width    uint
+		height   uint
+		duration time.Duration
+	}{
+		{"png", pngData, schema.MediaImagePNG, 64, 48, 0},
+		{"jpeg", jpegData, schema.MediaImageJPG, 64, 48, 0},
+		{"svg", []byte(`<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="120px" viewBox="0 0 120 80"></svg>`),
+			schema.MediaImageSVG, 120, 80, 0},
+		{"svg after a long prolog", []byte(`<?xml version="1.0"?>
+<!-- ` + strings.Repeat("generated by an illustrator ", 40) + `-->
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<svg xmlns="http://www.w3.org/2000/svg" width="120" height="80"></svg>`),
+			schema.MediaImageSVG, 120, 80, 0},
+		{"mp3", audioFrames(100, 0), schema.MediaAudioMP3, 0, 0, 2606250 * time.Microsecond},
+		{"id3", tagged, schema.MediaAudioMP3, 0, 0, 260625 * time.Microsecond},
+		{"xing", audioFrames(2, 1000), schema.MediaAudioMP3, 0, 0, 26122448979},
+		{"long xing", audioFrames(2, math.MaxUint32), schema.MediaAudioMP3, 0, 0, 112195064032653061},
+		{"mp4", movie("isom"), schema.MediaVideoMP4, 640, 360, 7500 * time.Millisecond},
+		{"mov", movie("qt  "), schema.MediaVideoMOV, 640, 360, 7500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := schema.SniffMedia(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := schema.MediaInfo{MimeType: tt.mime, Size: int64(len(tt.data)),
+				Width: tt.width, Height: tt.height, Duration: tt.duration}
+			if info != want {
+				t.Errorf("SniffMedia() = %+v, want %+v", info, want)
+			}
+		})
+	}
+
+	for _, data := range [][]byte{[]byte("GIF89a"), []byte("plain text"), nil} {
+		if _, err := schema.SniffMedia(bytes.NewReader(data), int64(len(data))); !errors.Is(err, schema.ErrMediaType) {
+			t.Errorf("SniffMedia(%q) = %v, want ErrMediaType", data, err)
+		}
+	}
+	// A version 1 movie header with a 64-bit duration, of more seconds than a
+	// time.Duration can hold.
+	mvhd := make([]byte, 112)
+	mvhd[0] = 1
+	binary.BigEndian.PutUint32(mvhd[20:], 1)
+	binary.BigEndian.PutUint64(mvhd[24:], math.MaxUint64)
+	endless := append(box("ftyp", []byte("isom"), make([]byte, 4)), box("moov", box("mvhd", mvhd))...)
+	if _, err := schema.SniffMedia(bytes.NewReader(endless), int64(len(endless))); !errors.Is(err, schema.ErrMediaCorrupt) {
+		t.Errorf("expected ErrMediaCorrupt for an overlong movie, got %v", err)
+	}
+	truncated := pngData[:12]
+	if _, err := schema.SniffMedia(bytes.NewReader(truncated), 12); !errors.Is(err, schema.ErrMediaCorrupt) {
+		t.Errorf("expected ErrMediaCorrupt for a truncated PNG, got %v", err)
+	}
+}
+
+func TestCheckMedia(t *testing.T) {
+	fsys := fstest.MapFS{
+		"audio/bells.mp3": {Data: audioFrames(10, 0)},
+		"img/tower.jpg":   {Data: movie("isom")},
+	}
+	info, err := schema.CheckMedia(fsys, schema.MediaRef{
+		MimeType: schema.MediaAudioMP3, MediaURL: "audio/bells.mp3"})
+	if err != nil || info.MimeType != schema.MediaAudioMP3 {
+		t.Errorf("CheckMedia() = %+v, %v", info, err)
+	}
+	info, err = schema.CheckMedia(fsys, schema.MediaRef{
+		MimeType: schema.MediaImageJPG, MediaURL: "img/tower.jpg"})
+	if !errors.Is(err, schema.ErrMediaMismatch) || info.MimeType != schema.MediaVideoMP4 {
+		t.Errorf("expected a mismatch with the MP4 content, got %+v, %v", info, err)
+	}
+	if _, err := schema.CheckMedia(fsys, schema.MediaRef{MediaURL: "missing.png"}); err == nil {
+		t.Error("expected an error for missing media")
+	}
+}
+
+func TestMediaRefResolve(t *testing.T) {
+	tests := []struct {
+		base string
+		ref  string
+		want string
+	}{
+		{"https://q-party.kevindamm.com/media", "img/tower.jpg",
+			"https://q-party.kevindamm.com/media/img/tower.jpg"},
+		{"https://q-party.kevindamm.com/media/", "spoken/a b.mp3",
+			"https://q-party.kevindamm.com/media/spoken/a%20b.mp3"},
+		{"https://localhost", "clue.png", "https://localhost/clue.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			base, _ := url.Parse(tt.base)
+			got, err := schema.MediaRef{MediaURL: tt.ref}.Resolve(base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	base, _ := url.Parse("https://localhost/media")
+	if _, err := (schema.MediaRef{MediaURL: "../secret"}).Resolve(base); !errors.Is(err, schema.ErrMediaURL) {
+		t.Errorf("expected ErrMediaURL, got %v", err)
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/mediastore/s3.go
+
+package mediastore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+// Keeps media in an S3-compatible bucket, such as R2 or MinIO, signing each
+// request with AWS Signature Version 4.
+type S3MediaStore struct {
+	// The URL of the bucket, either path-style (https://host/bucket) or
+	// virtual-hosted (https://bucket.host).
+	Endpoint  *url.URL
+	Region    string // "auto" for R2
+	AccessKey string
+	SecretKey string
+
+	// Optional, defaults to http.DefaultClient and time.Now.
+	Client *http.Client
+	Clock  func() time.Time
+}
+
+func NewS3MediaStore(endpoint, region, accessKey, secretKey string) (*S3MediaStore, error) {
+	bucket, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if bucket.Scheme == "" || bucket.Host == "" {
+		return nil, fmt.Errorf("bucket endpoint must be an absolute URL: %q", endpoint)
+	}
+	return &S3MediaStore{
+		Endpoint:  bucket,
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}, nil
+}
+
+// Streams the content without signing it (as UNSIGNED-PAYLOAD).  The bucket
+// needs its length up front, so content of unknown length (not a bytes.Reader,
+// strings.Reader or other io.Seeker) is first copied to a temporary file.
+func (store *S3MediaStore) Put(ctx context.Context, key string, content io.Reader, mime schema.MimeType) error {
+	if err := checkMediaKey(key); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	size, ok := contentLength(content)
+	if !ok {
+		spooled, err := spool(ctx, content)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(spooled.Name())
+		defer spooled.Close()
+		content = spooled
+		size, _ = contentLength(spooled)
+	}
+	if size == 0 {
+		content = http.NoBody
+	}
+
+	request, err := store.request(ctx, http.MethodPut, key, nil, content, size)
+	if err != nil {
+		return err
+	}
+	if mime != "" {
+		request.Header.Set("Content-Type", string(mime))
+	}
+	response, err := store.do(request, key)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// The number of bytes left in the content, if it can be known without reading.
+func contentLength(content io.Reader) (int64, bool) {
+	switch content := content.(type) {
+	case interface{ Len() int }:
+		return int64(content.Len()), true
+	case io.Seeker:
+		offset, err := content.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := content.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := content.Seek(offset, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - offset, true
+	}
+	return 0, false
+}
+
+// Copies the content to a temporary file, which the caller must close and
+// remove, ready to be read from the start.
+func spool(ctx context.Context, content io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "media-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(file, contextReader{ctx, content})
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+func (store *S3MediaStore) Get(ctx context.Context, key string) (io.ReadCloser, MediaObject, error) {
+	request, err := store.request(ctx, http.MethodGet, key, nil, nil, 0)
+	if err != nil {
+		return nil, MediaObject{}, err
+	}
+	response, err := store.do(request, key)
+	if err != nil {
+		return nil, MediaObject{}, err
+	}
+	return response.Body, s3MediaObject(key, response), nil
+}
+
+func (store *S3MediaStore) Stat(ctx context.Context, key string) (MediaObject, error) {
+	request, err := store.request(ctx, http.MethodHead, key, nil, nil, 0)
+	if err != nil {
+		return MediaObject{}, err
+	}
+	response, err := store.do(request, key)
+	if err != nil {
+		return MediaObject{}, err
+	}
+	response.Body.Close()
+	return s3MediaObject(key, response), nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// The type of each is inferred from its key's extension, lists do not include
+// the type that the media was Put with.
+func (store *S3MediaStore) List(ctx context.Context, prefix string) ([]MediaObject, error) {
+	var objects []MediaObject
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		request, err := store.request(ctx, http.MethodGet, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		response, err := store.do(request, prefix)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(response.Body).Decode(&result)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range result.Contents {
+			mime, _ := schema.MimeTypeByExtension(content.Key)
+			objects = append(objects, MediaObject{
+				Key: content.Key, MimeType: mime,
+				Size: content.Size, Modified: content.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// A presigned GET of the media.  The expiry is clamped to what S3 allows, from
+// one second up to a week.
+func (store *S3MediaStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := checkMediaKey(key); err != nil {
+		return "", err
+	}
+	expires = min(max(expires, time.Second), maxPresignedExpiry)
+	target := store.objectURL(key)
+	now := store.now()
+	scope := store.scope(now)
+	query := target.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", store.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(amzTimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	target.RawQuery = canonicalQuery(query)
+
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		target.EscapedPath(),
+		target.RawQuery,
+		"host:" + target.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	target.RawQuery += "&X-Amz-Signature=" + store.signature(now, scope, canonical)
+	return target.String(), nil
+}
+
+const (
+	amzTimeFormat      = "20060102T150405Z"
+	maxPresignedExpiry = 7 * 24 * time.Hour
+	// The SHA-256 of an empty payload, for requests without a body.
+	emptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+func (store *S3MediaStore) now() time.Time {
+	if store.Clock != nil {
+		return store.Clock().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (store *S3MediaStore) objectURL(key string) *url.URL {
+	target := *store.Endpoint
+	base := strings.TrimSuffix(store.Endpoint.Path, "/")
+	target.Path = base + "/" + key
+	target.RawPath = awsEscape(base, true) + "/" + awsEscape(key, true)
+	return &target
+}
+
+// Builds a request for the key (or the bucket, if the key is empty) signed with
+// the Authorization header.  A body of the given size is sent unsigned.
+func (store *S3MediaStore) request(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64) (*http.Request, error) {
+	if key != "" {
+		if err := checkMediaKey(key); err != nil {
+			return nil, err
+		}
+	}
+	target := store.objectURL(key)
+	target.RawQuery = canonicalQuery(query)
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	payload := emptyPayload
+	if body != nil {
+		request.ContentLength = size
+		payload = "UNSIGNED-PAYLOAD"
+	}
+
+	now := store.now()
+	request.Header.Set("X-Amz-Date", now.Format(amzTimeFormat))
+	request.Header.Set("X-Amz-Content-Sha256", payload)
+
+	signed := "host;x-amz-content-sha256;x-amz-date"
+	canonical := strings.Join([]string{
+		method,
+		target.EscapedPath(),
+		target.RawQuery,
+		"host:" + target.Host + "\n" +
+			"x-amz-content-sha256:" + payload + "\n" +
+			"x-amz-date:" + now.Format(amzTimeFormat) + "\n",
+		signed,
+		payload,
+	}, "\n")
+	scope := store.scope(now)
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		store.AccessKey, scope, signed, store.signature(now, scope, canonical)))
+	return request, nil
+}
+
+// Sends the request, returning ErrMediaNotFound for a missing key and an error
+// for any other unsuccessful status.
+func (store *S3MediaStore) do(request *http.Request, key string) (*http.Response, error) {
+	client := store.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode/100 == 2 {
+		return response, nil
+	}
+	response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrMediaNotFound, key)
+	}
+	return nil, fmt.Errorf("%s %s: %s", request.Method, key, response.Status)
+}
+
+func (store *S3MediaStore) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + store.Region + "/s3/aws4_request"
+}
+
+func (store *S3MediaStore) signature(now time.Time, scope, canonical string) string {
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + now.Format(amzTimeFormat) + "\n" +
+		scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + store.SecretKey)
+	for _, part := range []string{now.Format("20060102"), store.Region, "s3", "aws4_request", toSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	return hex.EncodeToString(key)
+}
+
+func s3MediaObject(key string, response *http.Response) MediaObject {
+	object := MediaObject{
+		Key:      key,
+		MimeType: schema.MimeType(response.Header.Get("Content-Type")),
+		Size:     response.ContentLength,
+	}
+	if size, err := strconv.ParseInt(response.Header.Get("Content-Length"), 10, 64); err == nil {
+		object.Size = size
+	}
+	object.Modified, _ = http.ParseTime(response.Header.Get("Last-Modified"))
+	return object
+}
+
+// The query string as signed: sorted by key, with each key and value escaped.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, key := range keys {
+		values := slices.Clone(query[key])
+		slices.Sort(values)
+		for _, value := range values {
+			parts = append(parts, awsEscape(key, false)+"="+awsEscape(value, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// Escapes all but the unreserved characters (and slashes, if kept) as URIs are
+// escaped for signing.
+func awsEscape(text string, keepSlash bool) string {
+	var builder strings.Builder
+	for _, char := range []byte(text) {
+		switch {
+		case 'A' <= char && char <= 'Z', 'a' <= char && char <= 'z', '0' <= char && char <= '9',
+			char == '-', char == '_', char == '.', char == '~', char == '/' && keepSlash:
+			builder.WriteByte(char)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", char)
+		}
+	}
+	return builder.String()
+}
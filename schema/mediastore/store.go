@@ -0,0 +1,255 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/mediastore/store.go
+
+package mediastore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+// Where media (clues and spoken responses) are kept, by key.  Keys are paths
+// relative to the store's root, as in MediaRef.MediaURL or "spoken/<name>".
+type MediaStore interface {
+	// Writes the media at the key, replacing any that is already there.
+	Put(ctx context.Context, key string, content io.Reader, mime schema.MimeType) error
+	// Opens the media at the key, which the caller must close.
+	Get(ctx context.Context, key string) (io.ReadCloser, MediaObject, error)
+	Stat(ctx context.Context, key string) (MediaObject, error)
+	// Lists the media whose keys start with the prefix, ordered by key.
+	List(ctx context.Context, prefix string) ([]MediaObject, error)
+	// A URL which grants access to read the media until it expires.
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+type MediaObject struct {
+	Key string
+	schema.MimeType
+	Size     int64
+	Modified time.Time
+}
+
+var (
+	ErrMediaNotFound  = errors.New("media not found")
+	ErrMediaSignature = errors.New("media URL signature is invalid or expired")
+)
+
+func checkMediaKey(key string) error {
+	return schema.MediaRef{MediaURL: key}.Validate()
+}
+
+// Stops a copy once the context is done, checked before each read.
+type contextReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+func (reader contextReader) Read(buffer []byte) (int, error) {
+	if err := reader.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return reader.Reader.Read(buffer)
+}
+
+// Keeps media as files within a local directory, such as for tests or when
+// self-hosting.  Signed URLs are relative to BaseURL, signed with Secret if it
+// is set (see Verify).
+type LocalMediaStore struct {
+	Root    string
+	BaseURL *url.URL
+	Secret  []byte
+}
+
+func NewLocalMediaStore(root string, baseURL string) (*LocalMediaStore, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalMediaStore{Root: root, BaseURL: base}, nil
+}
+
+func (store *LocalMediaStore) path(key string) (string, error) {
+	if err := checkMediaKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(store.Root, filepath.FromSlash(key)), nil
+}
+
+// The type is not kept, it is inferred from the key's extension when read, so
+// a type (if given) must be the one for the extension.  If the context is done
+// before the content is written, the key is left as it was.
+func (store *LocalMediaStore) Put(ctx context.Context, key string, content io.Reader, mime schema.MimeType) error {
+	filename, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if mime != "" {
+		if inferred, _ := schema.MimeTypeByExtension(key); inferred != mime {
+			return fmt.Errorf("%w: %q is not the type of %s", schema.ErrMediaMismatch, mime, key)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return schema.WriteFileAtomic(filename, func(writer io.Writer) error {
+		_, err := io.Copy(writer, contextReader{ctx, content})
+		return err
+	})
+}
+
+func (store *LocalMediaStore) Get(ctx context.Context, key string) (io.ReadCloser, MediaObject, error) {
+	filename, err := store.path(key)
+	if err != nil {
+		return nil, MediaObject{}, err
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, MediaObject{}, localMediaError(key, err)
+	}
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		file.Close()
+		if err == nil {
+			err = fmt.Errorf("%w: %s", ErrMediaNotFound, key)
+		}
+		return nil, MediaObject{}, err
+	}
+	return file, localMediaObject(key, stat), nil
+}
+
+func (store *LocalMediaStore) Stat(ctx context.Context, key string) (MediaObject, error) {
+	filename, err := store.path(key)
+	if err != nil {
+		return MediaObject{}, err
+	}
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return MediaObject{}, localMediaError(key, err)
+	}
+	if stat.IsDir() {
+		return MediaObject{}, fmt.Errorf("%w: %s", ErrMediaNotFound, key)
+	}
+	return localMediaObject(key, stat), nil
+}
+
+func (store *LocalMediaStore) List(ctx context.Context, prefix string) ([]MediaObject, error) {
+	var objects []MediaObject
+	err := fs.WalkDir(os.DirFS(store.Root), ".", func(key string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if key != "." && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// Skip the temporary files of writes in progress.
+		if strings.HasPrefix(path.Base(key), ".") || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, localMediaObject(key, stat))
+		return ctx.Err()
+	})
+	return objects, err
+}
+
+// The key's URL relative to BaseURL, with an expiry and signature if the store
+// has a Secret.  Without a Secret the URL is public and will not Verify.
+func (store *LocalMediaStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	resolved, err := schema.MediaRef{MediaURL: key}.Resolve(store.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	if len(store.Secret) > 0 {
+		expiry := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+		query := url.Values{}
+		query.Set("expires", expiry)
+		query.Set("signature", store.signature(key, expiry))
+		resolved.RawQuery = query.Encode()
+	}
+	return resolved.String(), nil
+}
+
+// Checks the signature of a URL from SignedURL, returning the key it is for.
+// Every URL fails to verify if the store has no Secret, since anyone could sign
+// it with an empty key.
+func (store *LocalMediaStore) Verify(signed *url.URL, now time.Time) (string, error) {
+	if len(store.Secret) == 0 {
+		return "", fmt.Errorf("%w: the store has no secret", ErrMediaSignature)
+	}
+	key := strings.TrimPrefix(signed.Path, strings.TrimSuffix(store.BaseURL.Path, "/")+"/")
+	if err := checkMediaKey(key); err != nil {
+		return "", err
+	}
+	query := signed.Query()
+	expiry := query.Get("expires")
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() > seconds ||
+		!hmac.Equal([]byte(query.Get("signature")), []byte(store.signature(key, expiry))) {
+		return "", fmt.Errorf("%w: %s", ErrMediaSignature, key)
+	}
+	return key, nil
+}
+
+func (store *LocalMediaStore) signature(key, expiry string) string {
+	mac := hmac.New(sha256.New, store.Secret)
+	mac.Write([]byte(key + "\n" + expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func localMediaObject(key string, stat fs.FileInfo) MediaObject {
+	mime, _ := schema.MimeTypeByExtension(key)
+	return MediaObject{Key: key, MimeType: mime, Size: stat.Size(), Modified: stat.ModTime()}
+}
+
+func localMediaError(key string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrMediaNotFound, key)
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/mediastore/store_test.go
+
+package mediastore_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindamm/q-party/schema"
+	"github.com/kevindamm/q-party/schema/mediastore"
+)
+
+// Puts, gets and lists media as any MediaStore should.
+func exerciseMediaStore(t *testing.T, store mediastore.MediaStore) {
+	t.Helper()
+	ctx := context.Background()
+	for key, content := range map[string]string{
+		"spoken/one.mp3": "first",
+		"spoken/two.mp3": "second",
+		"img/tower.jpg":  "tower",
+		"spokenword.mp3": "other",
+	} {
+		mime, _ := schema.MimeTypeByExtension(key)
+		if err := store.Put(ctx, key, strings.NewReader(content), mime); err != nil {
+			t.Fatalf("Put(%s) = %v", key, err)
+		}
+	}
+
+	reader, object, err := store.Get(ctx, "spoken/two.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil || string(content) != "second" {
+		t.Errorf("Get() read %q, %v", content, err)
+	}
+	if object.Size != 6 || object.MimeType != schema.MediaAudioMP3 {
+		t.Errorf("Get() object = %+v", object)
+	}
+
+	object, err = store.Stat(ctx, "img/tower.jpg")
+	if err != nil || object.Size != 5 || object.MimeType != schema.MediaImageJPG {
+		t.Errorf("Stat() = %+v, %v", object, err)
+	}
+	if _, err := store.Stat(ctx, "img/missing.jpg"); !errors.Is(err, mediastore.ErrMediaNotFound) {
+		t.Errorf("expected ErrMediaNotFound, got %v", err)
+	}
+	if _, _, err := store.Get(ctx, "img/missing.jpg"); !errors.Is(err, mediastore.ErrMediaNotFound) {
+		t.Errorf("expected ErrMediaNotFound, got %v", err)
+	}
+	if err := store.Put(ctx, "../escape.mp3", strings.NewReader(""), ""); !errors.Is(err, schema.ErrMediaURL) {
+		t.Errorf("expected ErrMediaURL, got %v", err)
+	}
+
+	// Content of unknown length, and content whose write is canceled.
+	unsized := struct{ io.Reader }{strings.NewReader("unsized")}
+	if err := store.Put(ctx, "img/unsized.png", unsized, schema.MediaImagePNG); err != nil {
+		t.Fatalf("Put() of unknown length = %v", err)
+	}
+	if object, err := store.Stat(ctx, "img/unsized.png"); err != nil || object.Size != 7 {
+		t.Errorf("Stat() = %+v, %v", object, err)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := store.Put(canceled, "img/canceled.png", strings.NewReader("canceled"), ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, err := store.Stat(ctx, "img/canceled.png"); !errors.Is(err, mediastore.ErrMediaNotFound) {
+		t.Errorf("expected a canceled Put to leave no media, got %v", err)
+	}
+
+	objects, err := store.List(ctx, "spoken/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	if strings.Join(keys, " ") != "spoken/one.mp3 spoken/two.mp3" {
+		t.Errorf("List() = %v", keys)
+	}
+}
+
+func TestLocalMediaStore(t *testing.T) {
+	store, err := mediastore.NewLocalMediaStore(t.TempDir(), "https://localhost/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseMediaStore(t, store)
+
+	// The type is inferred from the key, so it must agree with the extension.
+	err = store.Put(context.Background(), "img/tower.png", strings.NewReader("tower"), schema.MediaImageJPG)
+	if !errors.Is(err, schema.ErrMediaMismatch) {
+		t.Errorf("expected ErrMediaMismatch, got %v", err)
+	}
+	if _, err := store.Stat(context.Background(), "img/tower.png"); !errors.Is(err, mediastore.ErrMediaNotFound) {
+		t.Errorf("expected a mismatched Put to leave no media, got %v", err)
+	}
+
+	signed, err := store.SignedURL(context.Background(), "img/tower.jpg", time.Hour)
+	if err != nil || signed != "https://localhost/media/img/tower.jpg" {
+		t.Errorf("SignedURL() without a secret = %s, %v", signed, err)
+	}
+
+	// Anyone can sign with an empty secret, so no URL verifies without one.
+	expiry := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	forger := hmac.New(sha256.New, nil)
+	forger.Write([]byte("img/tower.jpg\n" + expiry))
+	forged, _ := url.Parse(signed + "?expires=" + expiry +
+		"&signature=" + hex.EncodeToString(forger.Sum(nil)))
+	if _, err := store.Verify(forged, time.Now()); !errors.Is(err, mediastore.ErrMediaSignature) {
+		t.Errorf("expected no signature to verify without a secret, got %v", err)
+	}
+
+	store.Secret = []byte("shh")
+	signed, err = store.SignedURL(context.Background(), "img/tower.jpg", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := url.Parse(signed)
+	if key, err := store.Verify(parsed, time.Now()); err != nil || key != "img/tower.jpg" {
+		t.Errorf("Verify() = %q, %v", key, err)
+	}
+	if _, err := store.Verify(parsed, time.Now().Add(2*time.Hour)); !errors.Is(err, mediastore.ErrMediaSignature) {
+		t.Errorf("expected an expired signature, got %v", err)
+	}
+	parsed.Path = "/media/img/other.jpg"
+	if _, err := store.Verify(parsed, time.Now()); !errors.Is(err, mediastore.ErrMediaSignature) {
+		t.Errorf("expected an invalid signature, got %v", err)
+	}
+}
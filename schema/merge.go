@@ -0,0 +1,269 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/merge.go
+
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// How a field of an existing match is combined with a newly imported value.
+// An empty incoming value never replaces an existing one, and an empty existing
+// value is always filled in, the strategy only decides between two values.
+type MergeStrategyEnum uint8
+
+const (
+	MERGE_KEEP_FIRST  MergeStrategyEnum = iota // keep the existing value
+	MERGE_OVERWRITE                            // replace with the incoming value
+	MERGE_UNION                                // add list elements not already present
+	MERGE_CONCAT_ONCE                          // append text not already included
+	MaxMergeStrategyEnum
+)
+
+var merge_names = [MaxMergeStrategyEnum]string{
+	"keep-first",
+	"overwrite",
+	"union",
+	"concat-once",
+}
+
+func (strategy MergeStrategyEnum) String() string {
+	if strategy >= MaxMergeStrategyEnum {
+		return "unknown"
+	}
+	return merge_names[strategy]
+}
+
+// The strategy for each field of MatchMetadata.  MERGE_UNION only applies to
+// lists and MERGE_CONCAT_ONCE only to text, elsewhere they keep the first value.
+type MergePolicy struct {
+	SeasonSlug  MergeStrategyEnum
+	ShowTitle   MergeStrategyEnum
+	AiredDate   MergeStrategyEnum
+	TapedDate   MergeStrategyEnum
+	Contestants MergeStrategyEnum
+	Media       MergeStrategyEnum
+	Comments    MergeStrategyEnum
+}
+
+// The policy used by MatchIndex.Update.  Lists are combined by their keys (the
+// contestant's PK and the media's URL) so that re-importing is idempotent.
+var DefaultMergePolicy = MergePolicy{
+	SeasonSlug:  MERGE_KEEP_FIRST,
+	ShowTitle:   MERGE_OVERWRITE,
+	AiredDate:   MERGE_KEEP_FIRST,
+	TapedDate:   MERGE_KEEP_FIRST,
+	Contestants: MERGE_UNION,
+	Media:       MERGE_UNION,
+	Comments:    MERGE_CONCAT_ONCE,
+}
+
+// Describes a field where the existing and incoming values disagreed, and which
+// of the values was kept.  Values that were combined (a list union or appended
+// comments) are not conflicts, only those where one of the values was dropped.
+type MergeConflict struct {
+	Match     MatchNumber
+	Field     string
+	Strategy  MergeStrategyEnum
+	Kept      any
+	Discarded any
+}
+
+func (conflict MergeConflict) String() string {
+	return fmt.Sprintf("match %d %s (%s): kept %v, discarded %v",
+		conflict.Match, conflict.Field, conflict.Strategy,
+		conflict.Kept, conflict.Discarded)
+}
+
+// Merges the metadata into the index using the fields' strategies in policy,
+// returning any conflicts between the existing and incoming values.
+func (episodes MatchIndex) Merge(metadata MatchMetadata, policy MergePolicy) []MergeConflict {
+	match := metadata.MatchNumber
+	episode, exists := episodes[match]
+	if !exists {
+		// Merging into an empty episode also removes duplicates within metadata.
+		episode = &MatchMetadata{MatchID: MatchID{MatchNumber: match}}
+		episodes[match] = episode
+	}
+
+	merge := merger{match: match}
+	mergeValue(&merge, "season", policy.SeasonSlug,
+		&episode.SeasonSlug, metadata.SeasonSlug, isZero, isEqual)
+	mergeText(&merge, "show_title", policy.ShowTitle,
+		&episode.ShowTitle, metadata.ShowTitle)
+	mergeValue(&merge, "aired", policy.AiredDate,
+		&episode.AiredDate, copyDate(metadata.AiredDate), isUndated, isSameDate)
+	mergeValue(&merge, "taped", policy.TapedDate,
+		&episode.TapedDate, copyDate(metadata.TapedDate), isUndated, isSameDate)
+	mergeList(&merge, "contestants", policy.Contestants,
+		&episode.Contestants, metadata.Contestants, ContestantID.Key, combineContestant)
+	mergeList(&merge, "media", policy.Media,
+		&episode.Media, metadata.Media, mediaKey, combineMedia)
+	mergeText(&merge, "comments", policy.Comments,
+		&episode.Comments, metadata.Comments)
+	return merge.conflicts
+}
+
+type merger struct {
+	match     MatchNumber
+	conflicts []MergeConflict
+}
+
+func (merge *merger) conflict(field string, strategy MergeStrategyEnum, kept, discarded any) {
+	merge.conflicts = append(merge.conflicts,
+		MergeConflict{merge.match, field, strategy, kept, discarded})
+}
+
+// Merges a single value with either of the MERGE_KEEP_FIRST or MERGE_OVERWRITE
+// strategies, any other strategy keeps the first value.
+func mergeValue[T any](
+	merge *merger,
+	field string,
+	strategy MergeStrategyEnum,
+	existing *T, incoming T,
+	empty func(T) bool,
+	equal func(T, T) bool,
+) {
+	switch {
+	case empty(incoming) || equal(*existing, incoming):
+		return
+	case empty(*existing):
+		*existing = incoming
+	case strategy == MERGE_OVERWRITE:
+		merge.conflict(field, strategy, incoming, *existing)
+		*existing = incoming
+	default:
+		merge.conflict(field, strategy, *existing, incoming)
+	}
+}
+
+// Text may also be merged by appending it as a paragraph, unless the existing
+// text already includes it as a whole paragraph (or run of paragraphs).
+func mergeText[T ~string](merge *merger, field string, strategy MergeStrategyEnum, existing *T, incoming T) {
+	if strategy != MERGE_CONCAT_ONCE {
+		mergeValue(merge, field, strategy, existing, incoming, isZero, isEqual)
+		return
+	}
+	switch {
+	case len(incoming) == 0 || strings.Contains(
+		"\n\n"+string(*existing)+"\n\n", "\n\n"+string(incoming)+"\n\n"):
+		return
+	case len(*existing) == 0:
+		*existing = incoming
+	default:
+		*existing = *existing + "\n\n" + incoming
+	}
+}
+
+// Lists may also be merged by their elements' keys.  Elements with the same key
+// are combined, keeping the existing element if they disagree.
+func mergeList[T comparable, K comparable](
+	merge *merger,
+	field string,
+	strategy MergeStrategyEnum,
+	existing *[]T, incoming []T,
+	key func(T) K,
+	combine func(T, T) (T, bool),
+) {
+	if strategy != MERGE_UNION {
+		mergeValue(merge, field, strategy, existing, incoming,
+			func(list []T) bool { return len(list) == 0 },
+			slices.Equal)
+		return
+	}
+
+	position := make(map[K]int, len(*existing))
+	for i, element := range *existing {
+		position[key(element)] = i
+	}
+	for _, element := range incoming {
+		i, found := position[key(element)]
+		if !found {
+			position[key(element)] = len(*existing)
+			*existing = append(*existing, element)
+			continue
+		}
+		combined, agreed := combine((*existing)[i], element)
+		if !agreed {
+			merge.conflict(field, strategy, (*existing)[i], element)
+			continue
+		}
+		(*existing)[i] = combined
+	}
+}
+
+func combineContestant(existing, incoming ContestantID) (ContestantID, bool) {
+	agreed := true
+	mergeField(&existing.Name, incoming.Name, &agreed)
+	return existing, agreed
+}
+
+func mediaKey(media MediaRef) string {
+	return media.MediaURL
+}
+
+func combineMedia(existing, incoming MediaRef) (MediaRef, bool) {
+	agreed := true
+	mergeField(&existing.MimeType, incoming.MimeType, &agreed)
+	return existing, agreed
+}
+
+// Fills in an empty field, clearing agreed if both are set but differ.
+func mergeField[T comparable](existing *T, incoming T, agreed *bool) {
+	var zero T
+	if *existing == zero {
+		*existing = incoming
+	} else if incoming != zero && *existing != incoming {
+		*agreed = false
+	}
+}
+
+func isZero[T comparable](value T) bool {
+	var zero T
+	return value == zero
+}
+
+func isEqual[T comparable](a, b T) bool {
+	return a == b
+}
+
+func isUndated(date *ShowDate) bool {
+	return date == nil || *date == ShowDate{}
+}
+
+func isSameDate(a, b *ShowDate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// The merged episode should not share its dates with the incoming metadata.
+func copyDate(date *ShowDate) *ShowDate {
+	if date == nil {
+		return nil
+	}
+	copied := *date
+	return &copied
+}
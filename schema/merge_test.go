@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/merge_test.go
+
+package schema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func scraped() schema.MatchMetadata {
+	return schema.MatchMetadata{
+		MatchID:   schema.MatchID{MatchNumber: 8000, SeasonSlug: "36"},
+		AiredDate: date(2019, 9, 9),
+		TapedDate: date(2019, 7, 30),
+		Contestants: []schema.ContestantID{
+			{PK: 101, Name: "Ada"}, {PK: 102}, {PK: 103, Name: "Cy"}},
+		Media:    []schema.MediaRef{{MediaURL: "spoken/8000-1"}},
+		Comments: "Season premiere.",
+	}
+}
+
+func TestUpdateIdempotent(t *testing.T) {
+	episodes := schema.MatchIndex{}
+	if conflicts := episodes.Update(scraped()); len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts on insert: %v", conflicts)
+	}
+	if conflicts := episodes.Update(scraped()); len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts on re-import: %v", conflicts)
+	}
+	if episode := episodes[8000]; !reflect.DeepEqual(*episode, scraped()) {
+		t.Errorf("re-import changed the episode:\n%+v\n%+v", *episode, scraped())
+	}
+}
+
+func TestUpdateMerge(t *testing.T) {
+	episodes := schema.MatchIndex{}
+	episodes.Update(scraped())
+	conflicts := episodes.Update(schema.MatchMetadata{
+		MatchID:   schema.MatchID{MatchNumber: 8000, SeasonSlug: "37", ShowTitle: "Premiere"},
+		TapedDate: date(2019, 7, 31),
+		Contestants: []schema.ContestantID{
+			{PK: 102, Name: "Bo"}, {PK: 103, Name: "Cyrus"}, {PK: 104, Name: "Di"}},
+		Media: []schema.MediaRef{
+			{MimeType: schema.MediaAudioMP3, MediaURL: "spoken/8000-1"}},
+		Comments: "Season premiere.",
+	})
+
+	episode := episodes[8000]
+	if episode.AiredDate == nil || *episode.AiredDate != *date(2019, 9, 9) {
+		t.Errorf("aired date was lost: %v", episode.AiredDate)
+	}
+	if *episode.TapedDate != *date(2019, 7, 30) {
+		t.Errorf("taped date = %s, expected the first value", episode.TapedDate)
+	}
+	if episode.ShowTitle != "Premiere" {
+		t.Errorf("show title = %q", episode.ShowTitle)
+	}
+	expected := []schema.ContestantID{
+		{PK: 101, Name: "Ada"}, {PK: 102, Name: "Bo"}, {PK: 103, Name: "Cy"}, {PK: 104, Name: "Di"}}
+	if !reflect.DeepEqual(episode.Contestants, expected) {
+		t.Errorf("contestants = %v\nexpected %v", episode.Contestants, expected)
+	}
+	if len(episode.Media) != 1 || episode.Media[0].MimeType != schema.MediaAudioMP3 {
+		t.Errorf("media = %v", episode.Media)
+	}
+	if episode.Comments != "Season premiere." {
+		t.Errorf("comments = %q", episode.Comments)
+	}
+
+	fields := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		fields[i] = conflict.Field
+	}
+	if !reflect.DeepEqual(fields, []string{"season", "taped", "contestants"}) {
+		t.Errorf("conflicts = %v", conflicts)
+	}
+	if conflicts[2].Kept != (schema.ContestantID{PK: 103, Name: "Cy"}) {
+		t.Errorf("kept contestant = %v", conflicts[2].Kept)
+	}
+}
+
+func TestMergePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   schema.MergePolicy
+		incoming schema.MatchMetadata
+		check    func(*schema.MatchMetadata) bool
+		fields   int
+	}{
+		{"overwrite taped date",
+			schema.MergePolicy{TapedDate: schema.MERGE_OVERWRITE},
+			schema.MatchMetadata{TapedDate: date(2019, 8, 1)},
+			func(episode *schema.MatchMetadata) bool {
+				return *episode.TapedDate == *date(2019, 8, 1) &&
+					*episode.AiredDate == *date(2019, 9, 9)
+			}, 1},
+		{"nil dates are ignored",
+			schema.MergePolicy{AiredDate: schema.MERGE_OVERWRITE},
+			schema.MatchMetadata{AiredDate: nil, TapedDate: &schema.ShowDate{}},
+			func(episode *schema.MatchMetadata) bool {
+				return *episode.AiredDate == *date(2019, 9, 9) &&
+					*episode.TapedDate == *date(2019, 7, 30)
+			}, 0},
+		{"concat new comments",
+			schema.MergePolicy{Comments: schema.MERGE_CONCAT_ONCE},
+			schema.MatchMetadata{Comments: "Rescheduled."},
+			func(episode *schema.MatchMetadata) bool {
+				return episode.Comments == "Season premiere.\n\nRescheduled."
+			}, 0},
+		{"concat part of a comment",
+			schema.MergePolicy{Comments: schema.MERGE_CONCAT_ONCE},
+			schema.MatchMetadata{Comments: "premiere."},
+			func(episode *schema.MatchMetadata) bool {
+				return episode.Comments == "Season premiere.\n\npremiere."
+			}, 0},
+		{"overwrite comments",
+			schema.MergePolicy{Comments: schema.MERGE_OVERWRITE},
+			schema.MatchMetadata{Comments: "Rescheduled."},
+			func(episode *schema.MatchMetadata) bool {
+				return episode.Comments == "Rescheduled."
+			}, 1},
+		{"keep first contestants",
+			schema.MergePolicy{Contestants: schema.MERGE_KEEP_FIRST},
+			schema.MatchMetadata{Contestants: []schema.ContestantID{{PK: 104}}},
+			func(episode *schema.MatchMetadata) bool {
+				return len(episode.Contestants) == 3
+			}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			episodes := schema.MatchIndex{}
+			episodes.Update(scraped())
+			tt.incoming.MatchNumber = 8000
+			conflicts := episodes.Merge(tt.incoming, tt.policy)
+			if !tt.check(episodes[8000]) {
+				t.Errorf("unexpected merge result %+v", *episodes[8000])
+			}
+			if len(conflicts) != tt.fields {
+				t.Errorf("conflicts = %v, expected %d", conflicts, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/phonetic.go
+
+package schema
+
+import "strings"
+
+// Returns the phonetic codes for a word, so that words which sound alike have
+// the same code even when spelled differently.  This follows the Metaphone
+// rules, and like Double Metaphone gives an alternate code where a spelling has
+// more than one common pronunciation (TH as in "Thomas", CH as in "Christ").
+// The alternate is the same as the primary when there is no ambiguity.
+func PhoneticCodes(word string) (primary, alternate string) {
+	letters := make([]byte, 0, len(word))
+	for _, r := range strings.ToUpper(word) {
+		if r >= 'A' && r <= 'Z' {
+			letters = append(letters, byte(r))
+		}
+	}
+	return metaphone(letters, false), metaphone(letters, true)
+}
+
+// True if the two words share either of their phonetic codes.
+func SoundsAlike(a, b string) bool {
+	primaryA, alternateA := PhoneticCodes(a)
+	primaryB, alternateB := PhoneticCodes(b)
+	if primaryA == "" || primaryB == "" {
+		return false
+	}
+	return primaryA == primaryB || primaryA == alternateB ||
+		alternateA == primaryB || alternateA == alternateB
+}
+
+func metaphone(word []byte, alternate bool) string {
+	if len(word) == 0 {
+		return ""
+	}
+	// Silent first letters, and X sounding like S.
+	switch {
+	case hasPrefix(word, "AE"), hasPrefix(word, "GN"), hasPrefix(word, "KN"),
+		hasPrefix(word, "PN"), hasPrefix(word, "WR"):
+		word = word[1:]
+	case word[0] == 'X':
+		word = append([]byte{'S'}, word[1:]...)
+	case hasPrefix(word, "WH"):
+		word = append([]byte{'W'}, word[2:]...)
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(word) {
+			return 0
+		}
+		return word[i]
+	}
+	next := func(i int, letters string) bool {
+		return at(i+1) != 0 && strings.IndexByte(letters, at(i+1)) >= 0
+	}
+	var code strings.Builder
+	for i := 0; i < len(word); i++ {
+		letter := word[i]
+		// Doubled letters sound the same as one, except for C (as in "accent").
+		if letter == at(i-1) && letter != 'C' {
+			continue
+		}
+		switch letter {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				code.WriteByte('A')
+			}
+		case 'B':
+			if !(i == len(word)-1 && at(i-1) == 'M') {
+				code.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case next(i, "IEY"):
+				if at(i-1) != 'S' {
+					code.WriteByte('S')
+				}
+			case next(i, "H"):
+				if at(i-1) == 'S' || alternate {
+					code.WriteByte('K')
+				} else {
+					code.WriteByte('X')
+				}
+				i++
+			default:
+				code.WriteByte('K')
+			}
+		case 'D':
+			if next(i, "G") && strings.IndexByte("EIY", at(i+2)) >= 0 && at(i+2) != 0 {
+				code.WriteByte('J')
+				i++
+			} else {
+				code.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case next(i, "H") && i+2 < len(word) && !isVowel(at(i+2)):
+				// Silent, as in "night".
+			case next(i, "N") && (i+2 == len(word) || string(word[i+1:]) == "NED"):
+			case next(i, "IEY") && at(i-1) != 'G':
+				code.WriteByte('J')
+			default:
+				code.WriteByte('K')
+			}
+		case 'H':
+			if isVowel(at(i+1)) && strings.IndexByte("CSPTG", at(i-1)) < 0 {
+				code.WriteByte('H')
+			}
+		case 'K':
+			if at(i-1) != 'C' {
+				code.WriteByte('K')
+			}
+		case 'P':
+			if next(i, "H") {
+				code.WriteByte('F')
+				i++
+			} else {
+				code.WriteByte('P')
+			}
+		case 'Q':
+			code.WriteByte('K')
+		case 'S':
+			switch {
+			case next(i, "H"):
+				code.WriteByte('X')
+				i++
+			case next(i, "I") && strings.IndexByte("OA", at(i+2)) >= 0 && at(i+2) != 0:
+				code.WriteByte('X')
+			case next(i, "C") && at(i+2) == 'H':
+				code.WriteString("SK")
+				i += 2
+			default:
+				code.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case next(i, "I") && strings.IndexByte("OA", at(i+2)) >= 0 && at(i+2) != 0:
+				code.WriteByte('X')
+			case next(i, "H"):
+				if alternate {
+					code.WriteByte('T')
+				} else {
+					code.WriteByte('0') // theta
+				}
+				i++
+			case next(i, "C") && at(i+2) == 'H':
+				// Silent, as in "witch".
+			default:
+				code.WriteByte('T')
+			}
+		case 'V':
+			code.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(at(i + 1)) {
+				code.WriteByte(letter)
+			}
+		case 'X':
+			code.WriteString("KS")
+		case 'Z':
+			code.WriteByte('S')
+		default: // F, J, L, M, N, R
+			code.WriteByte(letter)
+		}
+	}
+	return collapse(code.String())
+}
+
+// Adjacent sounds that are the same are only pronounced once ("Nietzsche").
+func collapse(code string) string {
+	collapsed := make([]byte, 0, len(code))
+	for i := 0; i < len(code); i++ {
+		if i == 0 || code[i] != code[i-1] {
+			collapsed = append(collapsed, code[i])
+		}
+	}
+	return string(collapsed)
+}
+
+func hasPrefix(word []byte, prefix string) bool {
+	return len(word) >= len(prefix) && string(word[:len(prefix)]) == prefix
+}
+
+func isVowel(letter byte) bool {
+	return letter != 0 && strings.IndexByte("AEIOU", letter) >= 0
+}
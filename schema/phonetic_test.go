@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/phonetic_test.go
+
+package schema_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func TestPhoneticCodes(t *testing.T) {
+	tests := []struct {
+		word, primary, alternate string
+	}{
+		{"Nietzsche", "NTSK", "NTSK"},
+		{"Shakespeare", "XKSPR", "XKSPR"},
+		{"Thomas", "0MS", "TMS"},
+		{"Christ", "XRST", "KRST"},
+		{"Knight", "NT", "NT"},
+		{"Phoenix", "FNKS", "FNKS"},
+		{"Schmidt", "SKMT", "SKMT"},
+		{"Xavier", "SFR", "SFR"},
+		{"Edge", "AJ", "AJ"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		primary, alternate := schema.PhoneticCodes(tt.word)
+		if primary != tt.primary || alternate != tt.alternate {
+			t.Errorf("PhoneticCodes(%q) = %s, %s; expected %s, %s",
+				tt.word, primary, alternate, tt.primary, tt.alternate)
+		}
+	}
+
+	if !schema.SoundsAlike("Thomas", "Tomas") || !schema.SoundsAlike("Kristof", "Christoph") {
+		t.Error("alternate codes were not compared")
+	}
+	if schema.SoundsAlike("Nile", "Niger") || schema.SoundsAlike("", "") {
+		t.Error("different words sound alike")
+	}
+}
+
+// The responses in testdata are stand-ins for those transcribed by the /speak
+// route, with the verdicts expected with and without phonetic matching.
+func TestJudgeTranscripts(t *testing.T) {
+	data, err := os.ReadFile("testdata/transcripts.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transcripts []struct {
+		Challenge schema.HostChallenge  `json:"challenge"`
+		Response  schema.PlayerResponse `json:"response"`
+		Phonetic  bool                  `json:"phonetic"`
+		Textual   bool                  `json:"textual"`
+	}
+	if err := json.Unmarshal(data, &transcripts); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range transcripts {
+		t.Run(tt.Response.Response, func(t *testing.T) {
+			spoken := schema.DefaultJudge.JudgeResponse(tt.Challenge, tt.Response)
+			if spoken.Correct != tt.Phonetic {
+				t.Errorf("transcribed verdict = %+v, expected %v", spoken, tt.Phonetic)
+			}
+			tt.Response.Transcribed = false
+			typed := schema.DefaultJudge.JudgeResponse(tt.Challenge, tt.Response)
+			if typed.Correct != tt.Textual {
+				t.Errorf("typed verdict = %+v, expected %v", typed, tt.Textual)
+			}
+			if spoken.Correct && !typed.Correct &&
+				(spoken.Reason != schema.REASON_PHONETIC || spoken.Confidence >= 0.9) {
+				t.Errorf("phonetic verdict = %+v", spoken)
+			}
+		})
+	}
+}
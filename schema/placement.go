@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/placement.go
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+var ErrPlacement = errors.New("not enough positions for the special challenges")
+
+type PlacementModeEnum uint8
+
+const (
+	PLACEMENT_HISTORICAL PlacementModeEnum = iota // weighted by past matches
+	PLACEMENT_UNIFORM                             // any position is as likely
+	MaxPlacementModeEnum
+)
+
+// How many special positions (daily doubles) each round has, and how they are
+// chosen.  Rounds that are not listed have none.
+type PlacementRules struct {
+	Specials map[RoundEnum]int
+	Mode     PlacementModeEnum
+}
+
+// One special position in Single and two in Double, placed as they have been.
+var ClassicPlacement = PlacementRules{
+	Specials: map[RoundEnum]int{ROUND_SINGLE: 1, ROUND_DOUBLE: 2},
+	Mode:     PLACEMENT_HISTORICAL,
+}
+
+// The rows and columns of the special positions in past matches, by round.
+// The zero value is an empty history, ready to use.
+type PlacementHistory struct {
+	rounds map[RoundEnum]*placementCounts
+}
+
+// Columns after this many (uncommon, even for house rules) are not counted.
+const maxPlacementColumns = 16
+
+type placementCounts struct {
+	columns [maxPlacementColumns + 1]int // indexed by position, column 0 unused
+	rows    [MaxBoardRows + 1]int
+}
+
+// Adds the special positions of each of the record's rounds to the history.
+// Columns past maxPlacementColumns are not counted.
+func (history *PlacementHistory) Add(record *MatchRecord) {
+	if history.rounds == nil {
+		history.rounds = make(map[RoundEnum]*placementCounts)
+	}
+	for _, round := range record.Rounds {
+		for _, challenge := range round.Challenges {
+			if !challenge.Special ||
+				challenge.Column > maxPlacementColumns || challenge.Index > MaxBoardRows {
+				continue
+			}
+			counts := history.rounds[round.Round]
+			if counts == nil {
+				counts = new(placementCounts)
+				history.rounds[round.Round] = counts
+			}
+			counts.columns[challenge.Column]++
+			counts.rows[challenge.Index]++
+		}
+	}
+}
+
+// The relative likelihood of a special challenge at the position, as the
+// product of how often its row and its column have had one in this round.
+func (history *PlacementHistory) Weight(round RoundEnum, position BoardPosition) int {
+	if history == nil {
+		return 0
+	}
+	counts := history.rounds[round]
+	if counts == nil || position.Column > maxPlacementColumns || position.Index > MaxBoardRows {
+		return 0
+	}
+	return counts.columns[position.Column] * counts.rows[position.Index]
+}
+
+// Chooses the round's special positions according to the rules, marking them
+// in the round's challenges (and clearing any previously marked).  When there
+// is more than one, each is in a different column if possible.  Historical
+// placement falls back to uniform when no history is weighted for the round.
+func (history *PlacementHistory) Place(round *RoundRecord, rules PlacementRules, seed uint64) ([]BoardPosition, error) {
+	count := rules.Specials[round.Round]
+	if count > len(round.Challenges) {
+		return nil, fmt.Errorf("%w: %d of %d positions",
+			ErrPlacement, count, len(round.Challenges))
+	}
+	random := rand.New(rand.NewPCG(seed, 0x64626c))
+
+	taken := make([]bool, len(round.Challenges))
+	columns := make(map[uint]bool)
+	var positions []BoardPosition
+	for range count {
+		weights := make([]int, len(round.Challenges))
+		total := 0
+		for _, distinct := range []bool{true, false} {
+			for i, challenge := range round.Challenges {
+				weights[i] = 0
+				if taken[i] || (distinct && columns[challenge.Column]) {
+					continue
+				}
+				weights[i] = 1
+				if rules.Mode == PLACEMENT_HISTORICAL {
+					weights[i] = history.Weight(round.Round, challenge.BoardPosition)
+				}
+				total += weights[i]
+			}
+			if total == 0 && rules.Mode == PLACEMENT_HISTORICAL {
+				// None of the positions have been special before, or there is no
+				// history for this round, so any of them is equally likely.
+				for i, challenge := range round.Challenges {
+					if !taken[i] && !(distinct && columns[challenge.Column]) {
+						weights[i] = 1
+						total++
+					}
+				}
+			}
+			if total > 0 {
+				break
+			}
+		}
+
+		choice := random.IntN(total)
+		for i, weight := range weights {
+			if choice < weight {
+				taken[i] = true
+				columns[round.Challenges[i].Column] = true
+				positions = append(positions, round.Challenges[i].BoardPosition)
+				break
+			}
+			choice -= weight
+		}
+	}
+
+	for i := range round.Challenges {
+		round.Challenges[i].Special = taken[i]
+	}
+	return positions, nil
+}
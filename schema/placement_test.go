@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/placement_test.go
+
+package schema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+// A 6x5 round where the given positions are special.
+func fullRound(round schema.RoundEnum, specials ...schema.BoardPosition) *schema.RoundRecord {
+	record := &schema.RoundRecord{Board: schema.Board{RoundID: schema.RoundID{Round: round}}}
+	for column := uint(1); column <= 6; column++ {
+		for index := uint(1); index <= 5; index++ {
+			position := schema.BoardPosition{Column: column, Index: index}
+			record.Challenges = append(record.Challenges, schema.BoardChallenge{
+				BoardPosition: position,
+				Special:       len(specials) > 0 && specials[0] == position,
+			})
+			if len(specials) > 0 && specials[0] == position {
+				specials = specials[1:]
+			}
+		}
+	}
+	return record
+}
+
+func specialPositions(round *schema.RoundRecord) []schema.BoardPosition {
+	var positions []schema.BoardPosition
+	for _, challenge := range round.Challenges {
+		if challenge.Special {
+			positions = append(positions, challenge.BoardPosition)
+		}
+	}
+	return positions
+}
+
+func TestPlaceHistorical(t *testing.T) {
+	var history schema.PlacementHistory
+	for range 3 {
+		history.Add(&schema.MatchRecord{Rounds: []schema.RoundRecord{
+			*fullRound(schema.ROUND_SINGLE, schema.BoardPosition{Column: 3, Index: 5}),
+			*fullRound(schema.ROUND_DOUBLE,
+				schema.BoardPosition{Column: 2, Index: 4},
+				schema.BoardPosition{Column: 5, Index: 4}),
+		}})
+	}
+	if weight := history.Weight(schema.ROUND_DOUBLE, schema.BoardPosition{Column: 5, Index: 4}); weight != 18 {
+		t.Errorf("Weight() = %d, expected 18", weight)
+	}
+
+	for seed := range uint64(10) {
+		single := fullRound(schema.ROUND_SINGLE, schema.BoardPosition{Column: 1, Index: 1})
+		positions, err := history.Place(single, schema.ClassicPlacement, seed)
+		expected := []schema.BoardPosition{{Column: 3, Index: 5}}
+		if err != nil || !reflect.DeepEqual(positions, expected) {
+			t.Errorf("Place(single, %d) = %v, %v", seed, positions, err)
+		}
+		if !reflect.DeepEqual(specialPositions(single), expected) {
+			t.Errorf("marked %v as special", specialPositions(single))
+		}
+
+		double := fullRound(schema.ROUND_DOUBLE)
+		positions, err = history.Place(double, schema.ClassicPlacement, seed)
+		if err != nil || len(positions) != 2 {
+			t.Fatalf("Place(double, %d) = %v, %v", seed, positions, err)
+		}
+		expected = []schema.BoardPosition{{Column: 2, Index: 4}, {Column: 5, Index: 4}}
+		if !reflect.DeepEqual(specialPositions(double), expected) {
+			t.Errorf("Place(double, %d) = %v", seed, positions)
+		}
+	}
+
+	// Without any history for the round, placement is uniform.
+	final := fullRound(schema.ROUND_TIEBREAKER)
+	rules := schema.PlacementRules{Specials: map[schema.RoundEnum]int{schema.ROUND_TIEBREAKER: 1}}
+	if positions, err := history.Place(final, rules, 1); err != nil || len(positions) != 1 {
+		t.Errorf("Place(tiebreaker) = %v, %v", positions, err)
+	}
+}
+
+// Columns past the eighth are counted, up to a limit, on house-rules boards.
+func TestPlacementWideBoard(t *testing.T) {
+	var history schema.PlacementHistory
+	wide := schema.RoundRecord{Board: schema.Board{RoundID: schema.RoundID{Round: schema.ROUND_SINGLE}},
+		Challenges: []schema.BoardChallenge{
+			{BoardPosition: schema.BoardPosition{Column: 10, Index: 2}, Special: true},
+			{BoardPosition: schema.BoardPosition{Column: 40, Index: 3}, Special: true},
+		}}
+	history.Add(&schema.MatchRecord{Rounds: []schema.RoundRecord{wide}})
+	if weight := history.Weight(schema.ROUND_SINGLE, schema.BoardPosition{Column: 10, Index: 2}); weight != 1 {
+		t.Errorf("Weight(column 10) = %d, expected 1", weight)
+	}
+	if weight := history.Weight(schema.ROUND_SINGLE, schema.BoardPosition{Column: 40, Index: 2}); weight != 0 {
+		t.Errorf("Weight(column 40) = %d, expected 0", weight)
+	}
+}
+
+func TestPlaceUniform(t *testing.T) {
+	var history *schema.PlacementHistory
+	rules := schema.PlacementRules{
+		Specials: map[schema.RoundEnum]int{schema.ROUND_DOUBLE: 6},
+		Mode:     schema.PLACEMENT_UNIFORM}
+
+	first, err := history.Place(fullRound(schema.ROUND_DOUBLE), rules, 42)
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	second, _ := history.Place(fullRound(schema.ROUND_DOUBLE), rules, 42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("the same seed placed %v and %v", first, second)
+	}
+	columns := make(map[uint]bool)
+	for _, position := range first {
+		columns[position.Column] = true
+	}
+	if len(columns) != 6 {
+		t.Errorf("specials %v are not in distinct columns", first)
+	}
+
+	// More specials than columns, so some must share a column.
+	rules.Specials[schema.ROUND_DOUBLE] = 8
+	if positions, err := history.Place(fullRound(schema.ROUND_DOUBLE), rules, 42); err != nil || len(positions) != 8 {
+		t.Errorf("Place(8) = %v, %v", positions, err)
+	}
+	rules.Specials[schema.ROUND_DOUBLE] = 31
+	if _, err := history.Place(fullRound(schema.ROUND_DOUBLE), rules, 42); !errors.Is(err, schema.ErrPlacement) {
+		t.Errorf("Place(31) error = %v", err)
+	}
+	if positions, _ := history.Place(fullRound(schema.ROUND_SINGLE), rules, 42); len(positions) != 0 {
+		t.Errorf("placed %v in a round without specials", positions)
+	}
+}
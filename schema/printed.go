@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/printed.go
+
+package schema
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writes the match's challenges as plain text for offline play, as a round of
+// PRINTED_MEDIA: each board by category (with Final last) followed by an answer
+// key.  Special positions are marked but there is nothing to select or wager.
+func WritePrintedMedia(writer io.Writer, record *MatchRecord) error {
+	type printed struct {
+		round      RoundEnum
+		category   CategoryMetadata
+		challenges []BoardChallenge
+	}
+	var columns []printed
+	for _, round := range record.Rounds {
+		for i, category := range round.Columns {
+			column := printed{round.Round, category, nil}
+			for _, challenge := range round.Challenges {
+				if challenge.Column == uint(i+1) {
+					column.challenges = append(column.challenges, challenge)
+				}
+			}
+			columns = append(columns, column)
+		}
+	}
+	if record.Final != nil {
+		columns = append(columns, printed{ROUND_FINAL, record.Final.Category,
+			[]BoardChallenge{{HostChallenge: record.Final.Challenge}}})
+	}
+
+	buffered := bufio.NewWriter(writer)
+	title := []string{round_names[PRINTED_MEDIA]}
+	if record.ShowTitle != "" {
+		title = append(title, record.ShowTitle)
+	}
+	if record.MatchNumber != 0 {
+		title = append(title, fmt.Sprintf("#%d", record.MatchNumber))
+	}
+	fmt.Fprintln(buffered, strings.Join(title, " "))
+
+	section := func(answers bool) {
+		round := RoundEnum(-1)
+		for _, column := range columns {
+			if column.round != round {
+				round = column.round
+				fmt.Fprintf(buffered, "\n== %s ==\n", RoundID{Round: round}.RoundName())
+			}
+			fmt.Fprintf(buffered, "\n%s\n", column.category.Name)
+			for _, challenge := range column.challenges {
+				label := "     "
+				if challenge.Value != 0 {
+					label = fmt.Sprintf("%5d", challenge.Value)
+				}
+				if challenge.Special && !answers {
+					label += " *"
+				} else {
+					label += "  "
+				}
+				text := strings.ReplaceAll(
+					ParseClue(challenge.ChallengeData).PlainText(), "\n", " ")
+				if answers {
+					text = strings.Join(challenge.Correct, " / ")
+				}
+				fmt.Fprintf(buffered, "%s %s\n", label, text)
+			}
+		}
+	}
+	section(false)
+	fmt.Fprint(buffered, "\n\nANSWERS\n")
+	section(true)
+	return buffered.Flush()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/printed_test.go
+
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func TestWritePrintedMedia(t *testing.T) {
+	var builder strings.Builder
+	if err := schema.WritePrintedMedia(&builder, testRecord()); err != nil {
+		t.Fatalf("WritePrintedMedia() error = %v", err)
+	}
+	expected := `[printed media] #8000
+
+== Single! ==
+
+ZOOLOGY
+  200   It has a trunk
+  400 * It has a pouch
+
+== Final! ==
+
+RIVERS
+        It flows north through Cairo
+
+
+ANSWERS
+
+== Single! ==
+
+ZOOLOGY
+  200   an elephant
+  400   a kangaroo
+
+== Final! ==
+
+RIVERS
+        the Nile
+`
+	if builder.String() != expected {
+		t.Errorf("printed:\n%s\nexpected:\n%s", builder.String(), expected)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/record.cue
+
+package schema
+
+// A complete transcript of a match, with everything needed to replay it.
+#MatchRecord: {
+  #MatchMetadata
+  rounds?: [...#RoundRecord]
+  final?: #FinalRecord
+  tiebreakers?: [...#RoundRecord]
+  scores?: [...#ScoreTimeline]
+}
+
+// A played board, with the challenge at each position and the selections made.
+#RoundRecord: {
+  #Board
+  challenges?: [...#BoardChallenge]
+  history?: [...#RecordedOutcome]
+}
+
+// The outcome of a selection, along with the contestant who responded to it.
+#RecordedOutcome: {
+  #ContestantID
+  #SelectionOutcome
+}
+
+// Special positions are the daily doubles (see MatchRound_Positions.special).
+#BoardChallenge: {
+  #BoardPosition
+  #HostChallenge
+  special?: bool
+}
+
+// Every contestant responds to the Final challenge, after wagering on it.
+#FinalRecord: {
+  #RoundID
+  category: #CategoryMetadata
+  challenge: #HostChallenge
+  responses?: [...#FinalResponse]
+}
+
+#FinalResponse: {
+  wager: #PlayerWager
+  response: #PlayerResponse
+  correct: bool
+}
+
+// A contestant's score after each of the match's selections.
+#ScoreTimeline: {
+  #ContestantID
+  scores?: [...#Value]
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/record.go
+
+package schema
+
+import (
+	_ "embed"
+	"errors"
+)
+
+//go:embed record.cue
+var schemaRecords string
+
+// A complete transcript of a match, with everything needed to replay it: each
+// round's board and challenges, the order they were selected in, and the Final.
+type MatchRecord struct {
+	MatchMetadata `json:",inline"`
+
+	Rounds []RoundRecord `json:"rounds,omitempty"`
+	Final  *FinalRecord  `json:"final,omitempty"`
+
+	// Played only when the leaders are tied after Final, see RecordFinal.
+	Tiebreakers []RoundRecord   `json:"tiebreakers,omitempty"`
+	Scores      []ScoreTimeline `json:"scores,omitempty"`
+}
+
+// A played board, with the challenge at each position and the selections made.
+type RoundRecord struct {
+	Board `json:",inline"`
+
+	Challenges []BoardChallenge  `json:"challenges,omitempty"`
+	History    []RecordedOutcome `json:"history,omitempty"`
+}
+
+// The outcome of a selection, along with the contestant who responded to it.
+type RecordedOutcome struct {
+	ContestantID     `json:",inline"`
+	SelectionOutcome `json:",inline"`
+}
+
+// The challenge at a board position.  Special positions are the daily doubles
+// (as in the `special` column of the MatchRound_Positions table), the wager is
+// that of the contestant who selected it.
+type BoardChallenge struct {
+	BoardPosition `json:",inline"`
+	HostChallenge `json:",inline"`
+
+	Special bool `json:"special,omitempty"`
+}
+
+// Every contestant responds to the Final challenge, after wagering on it.
+type FinalRecord struct {
+	RoundID `json:",inline"`
+
+	Category  CategoryMetadata `json:"category"`
+	Challenge HostChallenge    `json:"challenge"`
+	Responses []FinalResponse  `json:"responses,omitempty"`
+}
+
+type FinalResponse struct {
+	Wager    PlayerWager    `json:"wager"`
+	Response PlayerResponse `json:"response"`
+	Correct  bool           `json:"correct"`
+}
+
+// A contestant's score after each of the match's selections, that is, after
+// each outcome in the rounds' histories and then after each Final response.
+type ScoreTimeline struct {
+	ContestantID `json:",inline"`
+	Scores       []Value `json:"scores,omitempty"`
+}
+
+// Returns the challenge at the position, if the board had one there.
+func (round *RoundRecord) ChallengeAt(position BoardPosition) (*BoardChallenge, bool) {
+	for i := range round.Challenges {
+		if round.Challenges[i].BoardPosition == position {
+			return &round.Challenges[i], true
+		}
+	}
+	return nil, false
+}
+
+// Replays the rounds' histories and the Final wagers to find each contestant's
+// score timeline, with contestants in the order of the match's metadata.
+func (record *MatchRecord) ReplayScores() []ScoreTimeline {
+	timelines := make([]ScoreTimeline, len(record.Contestants))
+	scores := make([]Value, len(record.Contestants))
+	position := make(map[ContestantKey]int, len(record.Contestants))
+	for i, contestant := range record.Contestants {
+		timelines[i].ContestantID = contestant
+		position[contestant.Key()] = i
+	}
+
+	score := func(contestant ContestantID, delta Value) {
+		if i, found := position[contestant.Key()]; found {
+			scores[i] += delta
+		}
+		for i := range timelines {
+			timelines[i].Scores = append(timelines[i].Scores, scores[i])
+		}
+	}
+	for _, round := range record.Rounds {
+		for _, outcome := range round.History {
+			score(outcome.ContestantID, outcome.Delta)
+		}
+	}
+	if record.Final != nil {
+		for _, final := range record.Final.Responses {
+			delta := Value(final.Wager.Wager)
+			if !final.Correct {
+				delta = -delta
+			}
+			score(final.Wager.ContestantID, delta)
+		}
+	}
+	return timelines
+}
+
+// Replays the round's history on its board, returning the final board state.
+func (round *RoundRecord) Replay() (*BoardState, error) {
+	state, err := NewBoardState(round.Board)
+	if err != nil {
+		return nil, err
+	}
+	for _, outcome := range round.History {
+		if err := state.Apply(outcome.SelectionOutcome); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+// The contestants with the highest (positive) score after Final, more than one
+// if they are tied.  Tiebreakers do not change the scores.
+func (record *MatchRecord) Leaders() []ContestantID {
+	var leaders []ContestantID
+	var best Value
+	for _, timeline := range record.ReplayScores() {
+		if len(timeline.Scores) == 0 {
+			continue
+		}
+		score := timeline.Scores[len(timeline.Scores)-1]
+		if score <= 0 || score < best {
+			continue
+		}
+		if score > best {
+			best, leaders = score, nil
+		}
+		leaders = append(leaders, timeline.ContestantID)
+	}
+	return leaders
+}
+
+// The winner is the only leader, or the leader who first responded correctly
+// in a tiebreaker.  False if there is no winner (yet).
+func (record *MatchRecord) Winner() (ContestantID, bool) {
+	leaders := record.Leaders()
+	switch len(leaders) {
+	case 0:
+		return ContestantID{}, false
+	case 1:
+		return leaders[0], true
+	}
+	for _, round := range record.Tiebreakers {
+		for _, outcome := range round.History {
+			if !outcome.Correct {
+				continue
+			}
+			for _, leader := range leaders {
+				if leader.Key() == outcome.ContestantID.Key() {
+					return leader, true
+				}
+			}
+		}
+	}
+	return ContestantID{}, false
+}
+
+// The round that should be played next, or false if the match is over.  After
+// Final, tiebreakers are played until one of the tied leaders is correct.
+func (record *MatchRecord) NextRound() (RoundEnum, bool) {
+	played := make(map[RoundEnum]bool)
+	for _, round := range record.Rounds {
+		played[round.Round] = true
+	}
+	switch {
+	case !played[ROUND_SINGLE]:
+		return ROUND_SINGLE, true
+	case !played[ROUND_DOUBLE]:
+		return ROUND_DOUBLE, true
+	case record.Final == nil:
+		return ROUND_FINAL, true
+	}
+	if _, won := record.Winner(); !won && len(record.Leaders()) > 1 {
+		return ROUND_TIEBREAKER, true
+	}
+	return ROUND_UNKNOWN, false
+}
+
+// Adds a tiebreaker round for the challenge, if one is needed next, and returns
+// its index in Tiebreakers.  RecordFinal and RecordTiebreaker call this when
+// needed, it may also be called to end a tiebreaker that no one responded to.
+func (record *MatchRecord) BeginTiebreaker(category CategoryMetadata, challenge HostChallenge) (int, bool) {
+	if next, ok := record.NextRound(); !ok || next != ROUND_TIEBREAKER {
+		return -1, false
+	}
+	position := BoardPosition{Column: 1, Index: 1}
+	record.Tiebreakers = append(record.Tiebreakers, RoundRecord{
+		Board: Board{
+			RoundID: RoundID{Episode: record.MatchNumber, Round: ROUND_TIEBREAKER},
+			Columns: []CategoryMetadata{category}},
+		Challenges: []BoardChallenge{{BoardPosition: position, HostChallenge: challenge}},
+	})
+	return len(record.Tiebreakers) - 1, true
+}
+
+var (
+	ErrNoTiebreaker     = errors.New("no tiebreaker is being played")
+	ErrTiebreakerSource = errors.New("no challenge for the next tiebreaker")
+)
+
+// Supplies the category and challenge for each tiebreaker as it begins, or
+// false if there are none left to play.
+type TiebreakerSource func() (CategoryMetadata, HostChallenge, bool)
+
+// Records the Final round.  If the leaders are tied after it, the first
+// tiebreaker begins with a challenge from source.  Returns the index of the
+// tiebreaker to be played (in Tiebreakers), or -1 if the match is over.
+func (record *MatchRecord) RecordFinal(final FinalRecord, source TiebreakerSource) (int, error) {
+	record.Final = &final
+	return record.continueTiebreakers(source)
+}
+
+// Records a response to the current tiebreaker.  Once each of the leaders has
+// responded without a correct response, the next tiebreaker begins with another
+// challenge from source.  Returns the index of the tiebreaker to be played, or
+// -1 if the match is over.
+func (record *MatchRecord) RecordTiebreaker(outcome RecordedOutcome, source TiebreakerSource) (int, error) {
+	current := len(record.Tiebreakers) - 1
+	if next, ok := record.NextRound(); !ok || next != ROUND_TIEBREAKER || current < 0 {
+		return -1, ErrNoTiebreaker
+	}
+	tiebreaker := &record.Tiebreakers[current]
+	state, err := tiebreaker.Replay()
+	if err != nil {
+		return current, err
+	}
+	if err := state.Apply(outcome.SelectionOutcome); err != nil {
+		return current, err
+	}
+	tiebreaker.History = append(tiebreaker.History, outcome)
+	return record.continueTiebreakers(source)
+}
+
+// Begins the next tiebreaker if one is needed and the current one (if any) is
+// over, returning the index of the tiebreaker to be played or -1.
+func (record *MatchRecord) continueTiebreakers(source TiebreakerSource) (int, error) {
+	if next, ok := record.NextRound(); !ok || next != ROUND_TIEBREAKER {
+		return -1, nil
+	}
+	current := len(record.Tiebreakers) - 1
+	if current >= 0 && !allResponded(record.Tiebreakers[current], record.Leaders()) {
+		return current, nil
+	}
+	category, challenge, ok := source()
+	if !ok {
+		return -1, ErrTiebreakerSource
+	}
+	index, _ := record.BeginTiebreaker(category, challenge)
+	return index, nil
+}
+
+// True if each of the contestants has responded in the round.
+func allResponded(round RoundRecord, contestants []ContestantID) bool {
+	responded := make(map[ContestantKey]bool, len(round.History))
+	for _, outcome := range round.History {
+		responded[outcome.ContestantID.Key()] = true
+	}
+	for _, contestant := range contestants {
+		if !responded[contestant.Key()] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/record_test.go
+
+package schema_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func testRecord() *schema.MatchRecord {
+	ada := schema.ContestantID{PK: 1, Name: "Ada"}
+	bo := schema.ContestantID{PK: 2, Name: "Bo"}
+	challenge := func(qid uint64, value schema.Value, clue, correct string) schema.HostChallenge {
+		return schema.HostChallenge{
+			Challenge: schema.Challenge{
+				ChallengeMetadata: schema.ChallengeMetadata{ChallengeID: schema.ChallengeID(qid), Value: value},
+				ChallengeData:     schema.ChallengeData{Clue: clue}},
+			Correct: []string{correct}}
+	}
+	selected := func(contestant schema.ContestantID, column, index uint, qid uint64, correct bool, delta schema.Value) schema.RecordedOutcome {
+		return schema.RecordedOutcome{
+			ContestantID: contestant,
+			SelectionOutcome: schema.SelectionOutcome{
+				BoardSelection: schema.BoardSelection{
+					ChallengeMetadata: schema.ChallengeMetadata{ChallengeID: schema.ChallengeID(qid)},
+					BoardPosition:     schema.BoardPosition{Column: column, Index: index}},
+				Correct: correct,
+				Delta:   delta}}
+	}
+
+	record := &schema.MatchRecord{
+		MatchMetadata: schema.MatchMetadata{
+			MatchID:     schema.MatchID{MatchNumber: 8000, SeasonSlug: "s36"},
+			AiredDate:   date(2019, 9, 9),
+			Contestants: []schema.ContestantID{ada, bo}},
+	}
+
+	single := schema.RoundRecord{
+		Board: schema.Board{
+			RoundID: schema.RoundID{Episode: 8000, Round: schema.ROUND_SINGLE},
+			Columns: []schema.CategoryMetadata{{Name: "ZOOLOGY", CategoryID: 11}}},
+		Challenges: []schema.BoardChallenge{
+			{BoardPosition: schema.BoardPosition{Column: 1, Index: 1},
+				HostChallenge: challenge(101, 200, "It has a trunk", "an elephant")},
+			{BoardPosition: schema.BoardPosition{Column: 1, Index: 2},
+				HostChallenge: challenge(102, 400, "It has a pouch", "a kangaroo"),
+				Special:       true},
+		},
+		History: []schema.RecordedOutcome{
+			selected(ada, 1, 1, 101, true, 200),
+			selected(ada, 1, 2, 102, false, -1000),
+		}}
+	single.Challenges[1].Wager = 1000
+	single.Missing = []schema.BoardPosition{{Column: 1, Index: 3}}
+	record.Rounds = append(record.Rounds, single)
+
+	record.Final = &schema.FinalRecord{
+		RoundID:   schema.RoundID{Episode: 8000, Round: schema.ROUND_FINAL},
+		Category:  schema.CategoryMetadata{Name: "RIVERS", CategoryID: 12},
+		Challenge: challenge(103, 0, "It flows north through Cairo", "the Nile"),
+		Responses: []schema.FinalResponse{
+			{Wager: schema.PlayerWager{ContestantID: ada, Wager: 100},
+				Response: schema.PlayerResponse{ContestantID: ada, Response: "Nile"},
+				Correct:  true},
+			{Wager: schema.PlayerWager{ContestantID: bo, Wager: 1},
+				Response: schema.PlayerResponse{ContestantID: bo, Response: "Amazon"}},
+		}}
+	record.Scores = record.ReplayScores()
+	return record
+}
+
+func recorded(contestant uint64, outcome schema.SelectionOutcome) schema.RecordedOutcome {
+	return schema.RecordedOutcome{ContestantID: schema.ContestantID{PK: contestant}, SelectionOutcome: outcome}
+}
+
+func TestMatchRecordRoundTrip(t *testing.T) {
+	record := testRecord()
+	var buffer bytes.Buffer
+	if err := schema.WriteMatchRecord(&buffer, record); err != nil {
+		t.Fatalf("WriteMatchRecord() error = %v", err)
+	}
+	err := schema.Validate(schema.KIND_MATCH_RECORD, buffer.Bytes())
+	if err := exceptDrift(err, categoryIDDrift); err != nil {
+		t.Fatalf("written record is not valid: %v\n%s", err, buffer.String())
+	}
+
+	loaded, err := schema.LoadMatchRecord(bytes.NewReader(buffer.Bytes()), schema.Strict())
+	if err != nil {
+		t.Fatalf("LoadMatchRecord() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, record) {
+		t.Errorf("round trip changed the record:\n%+v\n%+v", loaded, record)
+	}
+	if challenge, found := loaded.Rounds[0].ChallengeAt(schema.BoardPosition{Column: 1, Index: 2}); !found || !challenge.Special || challenge.Wager != 1000 {
+		t.Errorf("ChallengeAt(1, 2) = %+v, %v", challenge, found)
+	}
+	if _, found := loaded.Rounds[0].ChallengeAt(schema.BoardPosition{Column: 1, Index: 3}); found {
+		t.Error("found a challenge at a missing position")
+	}
+}
+
+func TestWriteMatchRecordValid(t *testing.T) {
+	id := schema.MatchMetadata{MatchID: schema.NewMatchID(8000)}
+	board := schema.Board{
+		RoundID: schema.RoundID{Episode: 8000, Round: schema.ROUND_SINGLE},
+		Columns: []schema.CategoryMetadata{{Name: "ZOOLOGY", CategoryID: 11}}}
+	tests := []struct {
+		name   string
+		record *schema.MatchRecord
+	}{
+		{"complete", testRecord()},
+		{"not yet played", &schema.MatchRecord{MatchMetadata: id}},
+		{"board without challenges", &schema.MatchRecord{MatchMetadata: id,
+			Rounds: []schema.RoundRecord{{Board: board}}}},
+		{"final without responses", &schema.MatchRecord{MatchMetadata: id,
+			Final: &schema.FinalRecord{
+				RoundID:   schema.RoundID{Episode: 8000, Round: schema.ROUND_FINAL},
+				Category:  schema.CategoryMetadata{Name: "RIVERS", CategoryID: 12},
+				Challenge: testRecord().Final.Challenge}}},
+		{"timeline without scores", &schema.MatchRecord{MatchMetadata: id,
+			Scores: []schema.ScoreTimeline{{ContestantID: schema.ContestantID{PK: 1}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := schema.WriteMatchRecord(&buffer, tt.record); err != nil {
+				t.Fatalf("WriteMatchRecord() error = %v", err)
+			}
+			err := schema.Validate(schema.KIND_MATCH_RECORD, buffer.Bytes())
+			if err := exceptDrift(err, categoryIDDrift); err != nil {
+				t.Errorf("written record is not valid: %v\n%s", err, buffer.String())
+			}
+		})
+	}
+}
+
+func TestMatchRecordReplayScores(t *testing.T) {
+	scores := testRecord().ReplayScores()
+	expected := [][]schema.Value{
+		{200, -800, -700, -700},
+		{0, 0, 0, -1},
+	}
+	if len(scores) != len(expected) {
+		t.Fatalf("ReplayScores() has %d timelines", len(scores))
+	}
+	for i, timeline := range scores {
+		if !reflect.DeepEqual(timeline.Scores, expected[i]) {
+			t.Errorf("%s scores = %v, expected %v", timeline.Name, timeline.Scores, expected[i])
+		}
+	}
+}
+
+func TestMatchRecordScoresByName(t *testing.T) {
+	// Contestants that were not found in the database are known by name only.
+	ada := schema.ContestantID{Name: "Ada"}
+	bo := schema.ContestantID{Name: "Bo"}
+	record := &schema.MatchRecord{
+		MatchMetadata: schema.MatchMetadata{Contestants: []schema.ContestantID{ada, bo}},
+		Rounds: []schema.RoundRecord{{History: []schema.RecordedOutcome{
+			{ContestantID: bo, SelectionOutcome: schema.SelectionOutcome{Correct: true, Delta: 400}},
+			{ContestantID: ada, SelectionOutcome: schema.SelectionOutcome{Correct: true, Delta: 200}},
+		}}},
+	}
+	scores := record.ReplayScores()
+	if !reflect.DeepEqual(scores[0].Scores, []schema.Value{0, 200}) ||
+		!reflect.DeepEqual(scores[1].Scores, []schema.Value{400, 400}) {
+		t.Errorf("ReplayScores() = %+v", scores)
+	}
+}
+
+func TestMatchRecordTiebreaker(t *testing.T) {
+	record := testRecord()
+	record.Final.Responses[0].Wager.Wager = 1000
+	record.Final.Responses[1].Wager.Wager = 200
+	record.Final.Responses[1].Correct = true
+	final := record.Final
+	record.Final = nil
+
+	drawn := 0
+	source := func() (schema.CategoryMetadata, schema.HostChallenge, bool) {
+		drawn++
+		return schema.CategoryMetadata{Name: "TIES"}, schema.HostChallenge{}, true
+	}
+	expectNext := func(expected schema.RoundEnum) {
+		t.Helper()
+		next, ok := record.NextRound()
+		if ok != (expected != schema.ROUND_UNKNOWN) || next != expected {
+			t.Fatalf("NextRound() = %d, %v; expected %d", next, ok, expected)
+		}
+	}
+	expectNext(schema.ROUND_DOUBLE)
+	record.Rounds = append(record.Rounds, schema.RoundRecord{
+		Board: schema.Board{RoundID: schema.RoundID{Round: schema.ROUND_DOUBLE}}})
+	expectNext(schema.ROUND_FINAL)
+	if _, started := record.BeginTiebreaker(schema.CategoryMetadata{}, schema.HostChallenge{}); started {
+		t.Fatal("began a tiebreaker before Final")
+	}
+	if _, err := record.RecordTiebreaker(recorded(1, outcome(1, 1, true)), source); !errors.Is(err, schema.ErrNoTiebreaker) {
+		t.Fatalf("RecordTiebreaker() before Final error = %v", err)
+	}
+
+	// Both finish with 200, so the first tiebreaker begins.
+	if index, err := record.RecordFinal(*final, source); index != 0 || err != nil {
+		t.Fatalf("RecordFinal() = %d, %v", index, err)
+	}
+	if leaders := record.Leaders(); len(leaders) != 2 {
+		t.Fatalf("Leaders() = %v", leaders)
+	}
+	expectNext(schema.ROUND_TIEBREAKER)
+
+	steps := []struct {
+		response schema.RecordedOutcome
+		index    int
+		err      error
+	}{
+		{recorded(1, outcome(1, 1, false)), 0, nil},
+		{recorded(1, outcome(1, 2, false)), 0, schema.ErrPositionBounds},
+		{recorded(2, outcome(1, 1, false)), 1, nil}, // neither was correct
+		{recorded(1, outcome(1, 1, false)), 1, nil},
+		{recorded(2, outcome(1, 1, true)), -1, nil},
+		{recorded(1, outcome(1, 1, true)), -1, schema.ErrNoTiebreaker},
+	}
+	for i, step := range steps {
+		index, err := record.RecordTiebreaker(step.response, source)
+		if index != step.index || !errors.Is(err, step.err) {
+			t.Errorf("RecordTiebreaker() step %d = %d, %v; expected %d, %v",
+				i+1, index, err, step.index, step.err)
+		}
+	}
+	if winner, won := record.Winner(); !won || winner.PK != 2 {
+		t.Errorf("Winner() = %v, %v", winner, won)
+	}
+	expectNext(schema.ROUND_UNKNOWN)
+	if len(record.Tiebreakers) != 2 || drawn != 2 {
+		t.Errorf("played %d tiebreakers (%d drawn), expected 2", len(record.Tiebreakers), drawn)
+	}
+
+	// Without another challenge, the tie cannot be broken.
+	record.Tiebreakers = nil
+	exhausted := func() (schema.CategoryMetadata, schema.HostChallenge, bool) {
+		return schema.CategoryMetadata{}, schema.HostChallenge{}, false
+	}
+	if index, err := record.RecordFinal(*final, exhausted); index != -1 || !errors.Is(err, schema.ErrTiebreakerSource) {
+		t.Errorf("RecordFinal() without a challenge = %d, %v", index, err)
+	}
+}
+
+func TestRoundRules(t *testing.T) {
+	tests := []struct {
+		round       schema.RoundEnum
+		positions   uint
+		wagers      schema.WagerEnum
+		buzzers     bool
+		interactive bool
+	}{
+		{schema.ROUND_SINGLE, 30, schema.WAGER_SPECIALS, true, true},
+		{schema.ROUND_DOUBLE, 30, schema.WAGER_SPECIALS, true, true},
+		{schema.ROUND_FINAL, 1, schema.WAGER_ALL, false, true},
+		{schema.ROUND_TIEBREAKER, 1, schema.WAGER_NONE, true, true},
+		{schema.PRINTED_MEDIA, 30, schema.WAGER_NONE, false, false},
+		{schema.MaxRoundEnum, 0, schema.WAGER_NONE, false, true},
+	}
+	for _, tt := range tests {
+		rules := tt.round.Rules()
+		if rules.Columns*rules.Rows != tt.positions || rules.Wagers != tt.wagers ||
+			rules.Buzzers != tt.buzzers || rules.Interactive != tt.interactive {
+			t.Errorf("Rules(%d) = %+v", tt.round, rules)
+		}
+	}
+
+	printed := schema.Board{RoundID: schema.RoundID{Round: schema.PRINTED_MEDIA},
+		Columns: make([]schema.CategoryMetadata, 6)}
+	state, _ := schema.NewBoardState(printed)
+	if err := state.Select(selection(1, 1)); !errors.Is(err, schema.ErrNotInteractive) {
+		t.Errorf("Select() on printed media error = %v", err)
+	}
+}
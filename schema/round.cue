@@ -30,7 +30,6 @@ package schema
   episode!: #MatchNumber
   round!: int & >=0 & <len(_round_names)
   round_name?: _round_names[round]
-  ...
 }
 
 // Display strings for the different rounds.
@@ -44,35 +43,41 @@ _round_names: [...string] & [
 ]
 
 // Board representation includes the minimum needed information for starting play.
-#Board: #RoundID & {
+#Board: {
+  #RoundID
   columns: [...#CategoryMetadata]
   missing?: [...#BoardPosition]
-  ...
 }
 
 // Board state includes the player selection and a minified representation of
 // which positions are still available, organized by category as a bitmap of
 // those (0) missing and (1) present, with least significant bit at the top.
-#BoardState: #Board & {
+#BoardState: {
+  #Board
   cat_bitmap: #BoardLayout
-  history: [...#SelectionOutcome]
+  history?: null | [...#SelectionOutcome]
 }
 
-// Shorthand representation (bitmap) for board-cell availability.
-#BoardLayout: bytes
+// Shorthand representation (bitmap) for board-cell availability, one byte per
+// column encoded as base64 (as encoding/json does for a []byte).
+#BoardLayout: string & =~"^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$"
 
 // A board position is identified by its column and (row) index.  These value
 // ranges may not need to be hard-coded, though the non-zero positive part will.
 #BoardPosition: {
   column!: int & >0 // typically 1..6
   index!: int & >0 // typically 1..5
-  ...
 }
 
 // Represents the board position and challenge, without contestant performance.
-#BoardSelection: #ContestantID & #ChallengeMetadata & #BoardPosition
+#BoardSelection: {
+  #ContestantID
+  #ChallengeMetadata
+  #BoardPosition
+}
 
-#SelectionOutcome: #BoardSelection & {
+#SelectionOutcome: {
+  #BoardSelection
   correct: bool
   delta: #Value
 }
@@ -27,7 +27,7 @@ import (
 	"fmt"
 )
 
-// go:embed round.cue
+//go:embed round.cue
 var schemaRounds string
 
 type RoundID struct {
@@ -71,6 +71,44 @@ var round_names = [6]string{
 	"Tiebreaker!!",
 	"[printed media]"}
 
+// How wagers are used in a round: only for its special positions (the daily
+// doubles), or by every contestant before responding (as in Final).
+type WagerEnum uint8
+
+const (
+	WAGER_NONE WagerEnum = iota
+	WAGER_SPECIALS
+	WAGER_ALL
+	MaxWagerEnum
+)
+
+// Describes the mechanics of a kind of round.
+type RoundRules struct {
+	Columns, Rows uint // the positions of its board
+	Wagers        WagerEnum
+	Buzzers       bool // contestants buzz in to respond
+	Interactive   bool // false if played offline, see WritePrintedMedia
+}
+
+// The rules for the round, or for an unknown round if it is not a RoundEnum.
+func (round RoundEnum) Rules() RoundRules {
+	if round < 0 || round >= MaxRoundEnum {
+		return round_rules[ROUND_UNKNOWN]
+	}
+	return round_rules[round]
+}
+
+var round_rules = [MaxRoundEnum]RoundRules{
+	{Interactive: true},
+	{Columns: 6, Rows: 5, Wagers: WAGER_SPECIALS, Buzzers: true, Interactive: true},
+	{Columns: 6, Rows: 5, Wagers: WAGER_SPECIALS, Buzzers: true, Interactive: true},
+	{Columns: 1, Rows: 1, Wagers: WAGER_ALL, Interactive: true},
+	// Sudden death, the first correct response wins and another challenge is
+	// played if no one responds correctly.
+	{Columns: 1, Rows: 1, Buzzers: true, Interactive: true},
+	{Columns: 6, Rows: 5},
+}
+
 type Board struct {
 	RoundID `json:",inline"`
 	Columns []CategoryMetadata `json:"columns"`
@@ -22,22 +22,393 @@
 
 package schema
 
-import "io"
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
 
-func LoadSeasonIndex(reader io.Reader) (*SeasonIndex, error) {
-	seasons := new(SeasonIndex)
+// Reads a season directory document and returns the index of its seasons.
+func LoadSeasonIndex(reader io.Reader, options ...LoadOption) (*SeasonIndex, error) {
+	directory, err := LoadSeasonDirectory(reader, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &directory.Seasons, nil
+}
+
+// Reads a season directory document (its version and index of seasons).
+func LoadSeasonDirectory(reader io.Reader, options ...LoadOption) (*SeasonDirectory, error) {
+	return load[SeasonDirectory](reader, options)
+}
+
+// Reads a single season's document, including its episodes and categories.
+func LoadSeason(reader io.Reader, options ...LoadOption) (*Season, error) {
+	return load[Season](reader, options)
+}
+
+// Reads the metadata for a single match (episode).
+func LoadMatch(reader io.Reader, options ...LoadOption) (*MatchMetadata, error) {
+	return load[MatchMetadata](reader, options)
+}
+
+// Reads the complete transcript of a match.
+func LoadMatchRecord(reader io.Reader, options ...LoadOption) (*MatchRecord, error) {
+	return load[MatchRecord](reader, options)
+}
+
+// Options for modifying the behavior of the Load* functions.
+type LoadOption func(*loader)
+
+// Strict decoding will reject any field that is not defined in the schema.
+// By default, unknown fields are silently ignored.
+func Strict() LoadOption {
+	return func(scanner *loader) {
+		scanner.strict = true
+	}
+}
+
+// Describes where in a document the decoding failed.  Path is a JSON path
+// (e.g. "$.seasons.s01.aired") and Line, Column are both 1-indexed.
+type DecodeError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (err DecodeError) Error() string {
+	return fmt.Sprintf("%s (line %d, column %d): %s",
+		err.Path, err.Line, err.Column, err.Err)
+}
+
+func (err DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// Returned (wrapped in a DecodeError) for fields not defined in the schema.
+var ErrUnknownField = errors.New("unknown field")
+
+type loader struct {
+	strict bool
+	data   []byte
+	dec    *json.Decoder
+}
 
-	return seasons, nil
+func load[T any](reader io.Reader, options []LoadOption) (*T, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	scanner := &loader{data: data}
+	for _, option := range options {
+		option(scanner)
+	}
+
+	value := new(T)
+	// The first pass walks the tokens alongside the type to find syntax errors
+	// and (when strict) unknown fields, so that both can be reported with their
+	// path in the document.  The second pass does the actual decoding.
+	scanner.dec = json.NewDecoder(bytes.NewReader(data))
+	if err := scanner.walk(reflect.TypeOf(value).Elem(), "$"); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, scanner.wrapError(err)
+	}
+	return value, nil
+}
+
+// Converts errors from encoding/json into a DecodeError with position info.
+func (scanner *loader) wrapError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset is after reading the offending byte, point at it instead.
+		return scanner.errorAt("$", syntaxErr.Offset-1, err)
+	case errors.As(err, &typeErr):
+		path := "$"
+		if typeErr.Field != "" {
+			path += "." + typeErr.Field
+		}
+		return scanner.errorAt(path, typeErr.Offset, err)
+	}
+	return err
 }
 
-func LoadSeason(reader io.Reader) (*Season, error) {
-	season := new(Season)
+func (scanner *loader) errorAt(path string, offset int64, err error) DecodeError {
+	line, column := 1, 1
+	offset = max(offset, 0)
+	if offset > int64(len(scanner.data)) {
+		offset = int64(len(scanner.data))
+	}
+	for _, b := range scanner.data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return DecodeError{Path: path, Line: line, Column: column, Err: err}
+}
+
+// Returns the offset of the next token, skipping whitespace and separators.
+func (scanner *loader) nextOffset() int64 {
+	offset := scanner.dec.InputOffset()
+	for offset < int64(len(scanner.data)) {
+		switch scanner.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+			continue
+		}
+		break
+	}
+	return offset
+}
 
-	return season, nil
+func (scanner *loader) token(path string) (json.Token, int64, error) {
+	offset := scanner.nextOffset()
+	token, err := scanner.dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset - 1
+		}
+		return nil, offset, scanner.errorAt(path, offset, err)
+	}
+	return token, offset, nil
 }
 
-func LoadMatch(reader io.Reader) (*MatchMetadata, error) {
-	episode := new(MatchMetadata)
+var (
+	jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Consumes the next value from the decoder, checking object keys against the
+// fields of the expected type.  Type mismatches are left for json.Unmarshal.
+func (scanner *loader) walk(expected reflect.Type, path string) error {
+	for expected.Kind() == reflect.Pointer {
+		expected = expected.Elem()
+	}
+	if reflect.PointerTo(expected).Implements(jsonUnmarshaler) ||
+		reflect.PointerTo(expected).Implements(textUnmarshaler) {
+		// Custom decoding may accept any shape of value, though structs that are
+		// written in their object form are still checked field by field.
+		if expected.Kind() != reflect.Struct || scanner.peek() != '{' {
+			return scanner.skip(path)
+		}
+	}
+
+	token, offset, err := scanner.token(path)
+	if err != nil {
+		return err
+	}
+	delim, isDelim := token.(json.Delim)
+	if !isDelim {
+		return scanner.checkScalar(expected, token, path, offset)
+	}
+
+	switch delim {
+	case '{':
+		switch expected.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Interface:
+		default:
+			return scanner.typeError("object", expected, path, offset)
+		}
+		var fields map[string]reflect.Type
+		if expected.Kind() == reflect.Struct {
+			fields = jsonFields(expected)
+		}
+		for scanner.dec.More() {
+			token, offset, err := scanner.token(path)
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			keypath := path + "." + key
+
+			var elem reflect.Type
+			switch expected.Kind() {
+			case reflect.Map:
+				elem = expected.Elem()
+			case reflect.Struct:
+				elem = lookupField(fields, key)
+				if elem == nil && scanner.strict {
+					return scanner.errorAt(keypath, offset,
+						fmt.Errorf("%w %q in %s", ErrUnknownField, key, expected.Name()))
+				}
+			}
+			if elem == nil {
+				err = scanner.skip(keypath)
+			} else {
+				err = scanner.walk(elem, keypath)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case '[':
+		switch expected.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Interface:
+		default:
+			return scanner.typeError("array", expected, path, offset)
+		}
+		var elem reflect.Type
+		if expected.Kind() == reflect.Slice || expected.Kind() == reflect.Array {
+			elem = expected.Elem()
+		}
+		for i := 0; scanner.dec.More(); i++ {
+			itempath := path + "[" + strconv.Itoa(i) + "]"
+			if elem == nil {
+				err = scanner.skip(itempath)
+			} else {
+				err = scanner.walk(elem, itempath)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Consume the closing delimiter.
+	_, _, err = scanner.token(path)
+	return err
+}
+
+// Returns the first byte of the next token without consuming it.
+func (scanner *loader) peek() byte {
+	offset := scanner.nextOffset()
+	if offset >= int64(len(scanner.data)) {
+		return 0
+	}
+	return scanner.data[offset]
+}
+
+// Checks that a scalar token (string, number, boolean) suits the expected type.
+func (scanner *loader) checkScalar(expected reflect.Type, token json.Token, path string, offset int64) error {
+	kind := expected.Kind()
+	if kind == reflect.Interface {
+		return nil
+	}
+	switch token.(type) {
+	case bool:
+		if kind != reflect.Bool {
+			return scanner.typeError("bool", expected, path, offset)
+		}
+	case float64:
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return scanner.typeError("number", expected, path, offset)
+		}
+	case string:
+		// Byte slices are encoded as base64 strings.
+		isBytes := kind == reflect.Slice && expected.Elem().Kind() == reflect.Uint8
+		if kind != reflect.String && !isBytes {
+			return scanner.typeError("string", expected, path, offset)
+		}
+	}
+	return nil
+}
+
+func (scanner *loader) typeError(value string, expected reflect.Type, path string, offset int64) error {
+	return scanner.errorAt(path, offset, &json.UnmarshalTypeError{
+		Value:  value,
+		Type:   expected,
+		Offset: offset,
+		Field:  strings.TrimPrefix(strings.TrimPrefix(path, "$"), "."),
+	})
+}
+
+// Consumes the next value (including any nested values) without checking it.
+func (scanner *loader) skip(path string) error {
+	depth := 0
+	for {
+		token, _, err := scanner.token(path)
+		if err != nil {
+			return err
+		}
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// Matches the key to a field name the same way encoding/json does, preferring
+// an exact match but falling back to a case-insensitive one.
+func lookupField(fields map[string]reflect.Type, key string) reflect.Type {
+	if field, exact := fields[key]; exact {
+		return field
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field
+		}
+	}
+	return nil
+}
 
-	return episode, nil
+// Collects the JSON field names of a struct type, including the fields of any
+// embedded structs.  Shallower fields take precedence over deeper ones.
+func jsonFields(structType reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	current := []reflect.Type{structType}
+	for len(current) > 0 {
+		var next []reflect.Type
+		found := make(map[string]reflect.Type)
+		for _, typ := range current {
+			for i := range typ.NumField() {
+				field := typ.Field(i)
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				fieldType := field.Type
+				if field.Anonymous && name == "" {
+					if fieldType.Kind() == reflect.Pointer {
+						fieldType = fieldType.Elem()
+					}
+					if fieldType.Kind() == reflect.Struct {
+						next = append(next, fieldType)
+						continue
+					}
+				}
+				if !field.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = field.Name
+				}
+				if _, shadowed := fields[name]; !shadowed {
+					found[name] = field.Type
+				}
+			}
+		}
+		for name, fieldType := range found {
+			fields[name] = fieldType
+		}
+		current = next
+	}
+	return fields
 }
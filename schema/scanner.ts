@@ -30,7 +30,7 @@ function LoadSeasonDir(): SeasonDirectoryType {
   // TODO load season directory from parameter or local file
   return {
     version: [0, 0, 0, 20250404],
-    seasons: new Map(),
+    seasons: {},
   }
 }
 
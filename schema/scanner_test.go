@@ -18,6 +18,173 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 // SOFTWARE.
 //
-// github:kevindamm/q-party/schema/reader_test.go
+// github:kevindamm/q-party/schema/scanner_test.go
 
 package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+const testSeasonDirectory = `{
+  "version": [0, 0, 0, 20250404],
+  "seasons": {
+    "s01": {
+      "slug": "s01",
+      "title": "Season 1",
+      "aired": {
+        "from": {"year": 1984, "month": 9, "day": 10},
+        "until": {"year": 1985, "month": 6, "day": 7}
+      },
+      "episode_count": 171
+    }
+  }
+}`
+
+func TestLoadSeasonDirectory(t *testing.T) {
+	directory, err := schema.LoadSeasonDirectory(
+		strings.NewReader(testSeasonDirectory), schema.Strict())
+	if err != nil {
+		t.Fatalf("LoadSeasonDirectory() error = %v", err)
+	}
+	if len(directory.Version) != 4 || directory.Version[3] != 20250404 {
+		t.Errorf("LoadSeasonDirectory() version = %v", directory.Version)
+	}
+	season, ok := directory.Seasons["s01"]
+	if !ok {
+		t.Fatalf("LoadSeasonDirectory() missing season s01")
+	}
+	if season.Title != "Season 1" || season.EpisodeCount != 171 {
+		t.Errorf("LoadSeasonDirectory() season = %+v", season)
+	}
+	if season.Aired.Until == nil || season.Aired.Until.Year != 1985 {
+		t.Errorf("LoadSeasonDirectory() aired = %+v", season.Aired)
+	}
+
+	index, err := schema.LoadSeasonIndex(strings.NewReader(testSeasonDirectory))
+	if err != nil {
+		t.Fatalf("LoadSeasonIndex() error = %v", err)
+	}
+	if len(*index) != 1 {
+		t.Errorf("LoadSeasonIndex() = %v", *index)
+	}
+}
+
+func TestLoadSeason(t *testing.T) {
+	season, err := schema.LoadSeason(strings.NewReader(`{
+  "slug": "s01",
+  "aired": {"from": {"year": 1984, "month": 9, "day": 10}},
+  "episodes": {
+    "1": {"match": 1, "season": "s01", "contestants": [{"cid": 7, "name": "Greg"}]}
+  },
+  "categories": {
+    "POTPOURRI": [{"title": "POTPOURRI", "catID": 12,
+                   "aired": {"year": 1984, "month": 9, "day": 10}}]
+  }
+}`), schema.Strict())
+	if err != nil {
+		t.Fatalf("LoadSeason() error = %v", err)
+	}
+	episode, ok := season.Episodes[1]
+	if !ok || episode.SeasonSlug != "s01" || len(episode.Contestants) != 1 {
+		t.Errorf("LoadSeason() episodes = %+v", season.Episodes)
+	}
+	if aired := season.Categories["POTPOURRI"]; len(aired) != 1 || aired[0].CategoryID != 12 {
+		t.Errorf("LoadSeason() categories = %+v", season.Categories)
+	}
+}
+
+func TestLoadMatch(t *testing.T) {
+	episode, err := schema.LoadMatch(strings.NewReader(`{
+  "match": 4596,
+  "show_title": "Show #4596",
+  "aired": {"year": 2004, "month": 9, "day": 6},
+  "media": [{"mime": "image/jpeg", "url": "4596/clue.jpg"}]
+}`))
+	if err != nil {
+		t.Fatalf("LoadMatch() error = %v", err)
+	}
+	if episode.MatchNumber != 4596 || episode.AiredDate == nil ||
+		episode.AiredDate.Year != 2004 || len(episode.Media) != 1 {
+		t.Errorf("LoadMatch() = %+v", episode)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		strict  bool
+		path    string
+		line    int
+		column  int
+		unknown bool
+	}{
+		{"unknown-lenient",
+			`{"match": 1, "bogus": true}`,
+			false, "", 0, 0, false},
+		{"unknown-strict",
+			"{\n  \"match\": 1,\n  \"bogus\": true\n}",
+			true, "$.bogus", 3, 3, true},
+		{"unknown-nested",
+			"{\"match\": 1,\n \"contestants\": [{\"cid\": 1}, {\"cid\": 2, \"age\": 3}]}",
+			true, "$.contestants[1].age", 2, 41, true},
+		{"wrong-type",
+			"{\"match\": 1,\n \"aired\": {\"year\": \"1999\"}}",
+			false, "$.aired.year", 2, 20, false},
+		{"syntax",
+			"{\"match\": 1,\n \"comments\": }",
+			false, "$.comments", 2, 14, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []schema.LoadOption
+			if tt.strict {
+				options = append(options, schema.Strict())
+			}
+			_, err := schema.LoadMatch(strings.NewReader(tt.json), options...)
+			if tt.path == "" {
+				if err != nil {
+					t.Errorf("LoadMatch() unexpected error %v", err)
+				}
+				return
+			}
+			var decodeErr schema.DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("LoadMatch() error = %v, want DecodeError", err)
+			}
+			if decodeErr.Path != tt.path ||
+				decodeErr.Line != tt.line || decodeErr.Column != tt.column {
+				t.Errorf("LoadMatch() error at %s:%d:%d, want %s:%d:%d",
+					decodeErr.Path, decodeErr.Line, decodeErr.Column,
+					tt.path, tt.line, tt.column)
+			}
+			if got := errors.Is(err, schema.ErrUnknownField); got != tt.unknown {
+				t.Errorf("errors.Is(ErrUnknownField) = %v, want %v", got, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestLoadMatchCompactDates(t *testing.T) {
+	episode, err := schema.LoadMatch(strings.NewReader(
+		`{"match": 4596, "aired": "2004/09/06", "taped": {"year": 2004, "month": 7, "day": 20}}`),
+		schema.Strict())
+	if err != nil {
+		t.Fatalf("LoadMatch() error = %v", err)
+	}
+	if episode.AiredDate.String() != "2004/09/06" || episode.TapedDate.String() != "2004/07/20" {
+		t.Errorf("LoadMatch() dates = %v, %v", episode.AiredDate, episode.TapedDate)
+	}
+
+	_, err = schema.LoadMatch(strings.NewReader(
+		`{"match": 4596, "aired": {"year": 2004, "month": 9, "day": 6, "weekday": 1}}`),
+		schema.Strict())
+	if !errors.Is(err, schema.ErrUnknownField) {
+		t.Errorf("LoadMatch() error = %v, want ErrUnknownField", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/schema.gen.ts
+
+// Code generated by zodgen from the schema Go types. DO NOT EDIT.
+
+import * as z from "@zod/mini"
+
+export const ShowDate = z.object({
+  year: z.int().check(z.minimum(1980)),
+  month: z.int().check(z.minimum(1), z.maximum(12)),
+  day: z.int().check(z.minimum(1), z.maximum(31)),
+})
+
+export const ShowDateRange = z.object({
+  from: z.optional(ShowDate),
+  until: z.optional(ShowDate),
+})
+
+export const SeasonSlug = z.string().check(z.regex(/^[a-zA-Z][0-9a-zA-Z_-]*$/)).brand("SeasonSlug")
+
+export const SeasonID = z.object({
+  slug: SeasonSlug,
+  title: z.optional(z.string()),
+})
+
+export const SeasonMetadata = z.extend(SeasonID, {
+  aired: ShowDateRange,
+  episode_count: z.optional(z.int()),
+  category_count: z.optional(z.int()),
+  challenge_count: z.optional(z.int()),
+  tripstump_count: z.optional(z.int()),
+})
+
+export const SeasonIndex = z.record(SeasonSlug, SeasonMetadata)
+
+export const SeasonDirectory = z.object({
+  version: z.array(z.int()),
+  seasons: SeasonIndex,
+})
+
+export const MatchNumber = z.int().check(z.positive()).brand("MatchNumber")
+
+export const MatchID = z.object({
+  match: MatchNumber,
+  show_title: z.optional(z.string()),
+  season: z.optional(SeasonSlug),
+})
+
+export const ContestantID = z.object({
+  cid: z.int().check(z.nonnegative()),
+  name: z.optional(z.string()),
+})
+
+export const MimeType = z.enum([
+  "image/jpeg",
+  "image/png",
+  "image/svg+xml",
+  "audio/mpeg",
+  "video/mp4",
+  "video/quicktime",
+])
+
+export const MediaRef = z.object({
+  mime: z.optional(MimeType),
+  url: z.string(),
+})
+
+export const MatchMetadata = z.extend(MatchID, {
+  aired: z.optional(ShowDate),
+  taped: z.optional(ShowDate),
+  contestants: z.optional(z.array(ContestantID)),
+  media: z.optional(z.array(MediaRef)),
+  comments: z.optional(z.string()),
+})
+
+export const MatchIndex = z.record(z.string().check(z.regex(/^\d+$/)), MatchMetadata)
+
+export const CategoryName = z.string().brand("CategoryName")
+
+export const CategoryID = z.int().check(z.nonnegative()).brand("CategoryID")
+
+export const CategoryMetadata = z.object({
+  title: CategoryName,
+  catID: CategoryID,
+})
+
+export const CategoryAired = z.extend(CategoryMetadata, {
+  aired: ShowDate,
+})
+
+export const CategoryIndex = z.record(CategoryName, z.array(CategoryAired))
+
+export const Season = z.extend(SeasonMetadata, {
+  episodes: MatchIndex,
+  categories: CategoryIndex,
+})
+
+export const BoardPosition = z.object({
+  column: z.int().check(z.positive()),
+  index: z.int().check(z.positive()),
+})
+
+export const Value = z.int().brand("Value")
+
+export const MatchStats = z.extend(MatchMetadata, {
+  single_count: z.optional(z.int()),
+  double_count: z.optional(z.int()),
+  challenge_count: z.optional(z.int()),
+  category_count: z.optional(z.int()),
+  triple_stumpers: z.optional(z.array(BoardPosition)),
+  stumped_value: z.optional(Value),
+})
+
+export const ChallengeID = z.int().check(z.nonnegative()).brand("ChallengeID")
+
+export const ChallengeMetadata = z.object({
+  qid: ChallengeID,
+  value: z.optional(Value),
+})
+
+export const ChallengeData = z.object({
+  clue: z.string(),
+  media: z.optional(z.array(MediaRef)),
+  category: z.optional(CategoryName),
+  comments: z.optional(z.string()),
+})
+
+export const Wager = z.int().check(z.nonnegative()).brand("Wager")
+
+export const Challenge = z.extend(ChallengeMetadata, {
+  ...ChallengeData.shape,
+  wager: z.optional(Wager),
+})
+
+export const Category = z.extend(CategoryMetadata, {
+  challenges: z.array(Challenge),
+  media: z.optional(z.array(MediaRef)),
+  comments: z.optional(z.string()),
+})
+
+export const HostChallenge = z.extend(Challenge, {
+  correct: z.array(z.string()),
+})
+
+export const PlayerWager = z.extend(ContestantID, {
+  ...ChallengeMetadata.shape,
+  wager: Wager,
+  comments: z.optional(z.string()),
+})
+
+export const PlayerResponse = z.extend(ContestantID, {
+  ...ChallengeMetadata.shape,
+  response: z.optional(z.string()),
+  transcribed: z.optional(z.boolean()),
+})
+
+export const Contestant = z.extend(ContestantID, {
+  name: z.string(),
+  occupation: z.optional(z.string()),
+  residence: z.optional(z.string()),
+  notes: z.optional(z.string()),
+  media: z.optional(z.array(MediaRef)),
+})
+
+export const Appearance = z.extend(ContestantID, {
+  episode: MatchID,
+})
+
+export const Career = z.extend(ContestantID, {
+  appearances: z.array(MatchID),
+  winnings: Value,
+})
+
+export const DataQualityEnum = z.int().check(z.nonnegative(), z.lt(8)).brand("DataQualityEnum")
+
+export const DataQuality = z.object({
+  dqID: DataQualityEnum,
+  quality: z.string(),
+})
+
+export const DataQualityJudgement = z.extend(ChallengeMetadata, {
+  quality: DataQualityEnum,
+  comments: z.string(),
+})
+
+export const RoundEnum = z.int().check(z.nonnegative(), z.lt(6)).brand("RoundEnum")
+
+export const RoundID = z.object({
+  episode: z.optional(MatchNumber),
+  round: z.optional(RoundEnum),
+})
+
+export const Board = z.extend(RoundID, {
+  columns: z.array(CategoryMetadata),
+  missing: z.optional(z.array(BoardPosition)),
+})
+
+export const BoardLayout = z.base64().brand("BoardLayout")
+
+export const BoardSelection = z.extend(ChallengeMetadata, {
+  ...BoardPosition.shape,
+})
+
+export const SelectionOutcome = z.extend(BoardSelection, {
+  correct: z.boolean(),
+  delta: Value,
+})
+
+export const BoardState = z.extend(Board, {
+  cat_bitmap: BoardLayout,
+  history: z.array(SelectionOutcome),
+})
+
+export const BoardChallenge = z.extend(BoardPosition, {
+  ...HostChallenge.shape,
+  special: z.optional(z.boolean()),
+})
+
+export const RecordedOutcome = z.extend(ContestantID, {
+  ...SelectionOutcome.shape,
+})
+
+export const RoundRecord = z.extend(Board, {
+  challenges: z.optional(z.array(BoardChallenge)),
+  history: z.optional(z.array(RecordedOutcome)),
+})
+
+export const FinalResponse = z.object({
+  wager: PlayerWager,
+  response: PlayerResponse,
+  correct: z.boolean(),
+})
+
+export const FinalRecord = z.extend(RoundID, {
+  category: CategoryMetadata,
+  challenge: HostChallenge,
+  responses: z.optional(z.array(FinalResponse)),
+})
+
+export const ScoreTimeline = z.extend(ContestantID, {
+  scores: z.optional(z.array(Value)),
+})
+
+export const MatchRecord = z.extend(MatchMetadata, {
+  rounds: z.optional(z.array(RoundRecord)),
+  final: z.optional(FinalRecord),
+  tiebreakers: z.optional(z.array(RoundRecord)),
+  scores: z.optional(z.array(ScoreTimeline)),
+})
@@ -28,7 +28,6 @@ package schema
 #SeasonName: {
   slug: #SeasonSlug
   title?: string
-  ...
 }
 
 #SeasonIndex: [#SeasonSlug]: #SeasonMetadata
@@ -40,17 +39,18 @@ package schema
 }
 
 // Metadata for a single season, has identity and some statistics.
-#SeasonMetadata: #SeasonName & {
+#SeasonMetadata: {
+  #SeasonName
   aired: #ShowDateRange
 
   episode_count?:   *0 | int & >0
   category_count?:  *0 | int & >0
   challenge_count?: *0 | int & >0
   tripstump_count?: *0 | int & >0
-  ...
 }
 
-#Season: #SeasonMetadata & {
+#Season: {
+  #SeasonMetadata
   episodes: #MatchIndex
   categories: #CategoryIndex
 }
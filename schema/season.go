@@ -22,9 +22,14 @@
 
 package schema
 
-import _ "embed"
+import (
+	_ "embed"
+	"slices"
+	"sort"
+	"strings"
+)
 
-// go:embed season.cue
+//go:embed season.cue
 var schemaSeasons string
 
 type SeasonSlug string
@@ -56,3 +61,124 @@ type Season struct {
 	Episodes       MatchIndex    `json:"episodes,inline"`
 	Categories     CategoryIndex `json:"categories,inline"`
 }
+
+// Adds the match's statistics to the season's totals.
+func (season *SeasonMetadata) AddMatchStats(stats MatchStats) {
+	season.CategoryCount += stats.CategoryCount
+	season.ChallengeCount += stats.ChallengeCount
+	season.TripStumpCount += len(stats.TripleStumpers)
+}
+
+// Returns the seasons ordered by when they began airing, then by their slug.
+// Seasons without a beginning date are first.
+func (index SeasonIndex) Sorted() []*SeasonMetadata {
+	seasons := make([]*SeasonMetadata, 0, len(index))
+	for _, season := range index {
+		seasons = append(seasons, season)
+	}
+	slices.SortFunc(seasons, func(a, b *SeasonMetadata) int {
+		if order := compareFrom(a.Aired.From, b.Aired.From); order != 0 {
+			return order
+		}
+		return strings.Compare(string(a.Slug), string(b.Slug))
+	})
+	return seasons
+}
+
+// Returns the season that aired on the given date, see SeasonCalendar.  The
+// calendar is built for each call, so it always reflects the current Seasons;
+// build one with SeasonIndex.Calendar to look up many dates.
+func (directory *SeasonDirectory) SeasonFor(date ShowDate) (*SeasonMetadata, bool) {
+	return directory.Seasons.Calendar().SeasonFor(date)
+}
+
+// Fills in the SeasonSlug of any episodes that are missing one, based on their
+// aired date.  Returns the number of episodes that were assigned a season.
+func (directory *SeasonDirectory) AssignSeasons(episodes MatchIndex) int {
+	calendar := directory.Seasons.Calendar()
+	assigned := 0
+	for _, episode := range episodes {
+		if episode.SeasonSlug != "" || episode.AiredDate == nil {
+			continue
+		}
+		if season, found := calendar.SeasonFor(*episode.AiredDate); found {
+			episode.SeasonSlug = season.Slug
+			assigned++
+		}
+	}
+	return assigned
+}
+
+// A sorted interval index over the seasons' aired date ranges.
+type SeasonCalendar struct {
+	// Seasons sorted by their beginning date and, for each index, the latest end
+	// date of all seasons up to and including that index (nil if unbounded).
+	seasons   []*SeasonMetadata
+	maxUntils []*ShowDate
+}
+
+// Builds the calendar for the index.  Seasons that have neither a beginning nor
+// an ending date are not included.
+func (index SeasonIndex) Calendar() *SeasonCalendar {
+	calendar := new(SeasonCalendar)
+	for _, season := range index.Sorted() {
+		if season.Aired.From == nil && season.Aired.Until == nil {
+			continue
+		}
+		maxUntil := season.Aired.Until
+		if count := len(calendar.maxUntils); count > 0 {
+			previous := calendar.maxUntils[count-1]
+			if previous == nil || (maxUntil != nil && previous.Compare(maxUntil) > 0) {
+				maxUntil = previous
+			}
+		}
+		calendar.seasons = append(calendar.seasons, season)
+		calendar.maxUntils = append(calendar.maxUntils, maxUntil)
+	}
+	return calendar
+}
+
+// Returns the season whose aired range contains date.  Where seasons overlap
+// (e.g. a tournament within a regular season), the one that began most recently
+// is returned.
+func (calendar *SeasonCalendar) SeasonFor(date ShowDate) (*SeasonMetadata, bool) {
+	seasons := calendar.SeasonsAt(date)
+	if len(seasons) == 0 {
+		return nil, false
+	}
+	return seasons[0], true
+}
+
+// Returns all seasons whose aired range contains date, most recent first.
+func (calendar *SeasonCalendar) SeasonsAt(date ShowDate) []*SeasonMetadata {
+	// The seasons after this index all begin after date.
+	count := sort.Search(len(calendar.seasons), func(i int) bool {
+		from := calendar.seasons[i].Aired.From
+		return from != nil && from.Compare(&date) > 0
+	})
+
+	var seasons []*SeasonMetadata
+	for i := count - 1; i >= 0; i-- {
+		if maxUntil := calendar.maxUntils[i]; maxUntil != nil && maxUntil.Compare(&date) < 0 {
+			// None of the seasons up to here are still airing at date.
+			break
+		}
+		if calendar.seasons[i].Aired.Contains(date) {
+			seasons = append(seasons, calendar.seasons[i])
+		}
+	}
+	return seasons
+}
+
+// Orders range beginnings, where nil (unbounded) is before any date.
+func compareFrom(a, b *ShowDate) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return +1
+	}
+	return a.Compare(b)
+}
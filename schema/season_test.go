@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/season_test.go
+
+package schema_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func testDirectory() *schema.SeasonDirectory {
+	season := func(slug string, from, until *schema.ShowDate) *schema.SeasonMetadata {
+		return &schema.SeasonMetadata{
+			SeasonID: schema.SeasonID{Slug: schema.SeasonSlug(slug)},
+			Aired:    schema.ShowDateRange{From: from, Until: until}}
+	}
+	return &schema.SeasonDirectory{
+		Version: []int{1, 0},
+		Seasons: schema.SeasonIndex{
+			"40":      season("40", date(2023, 9, 11), date(2024, 7, 26)),
+			"39":      season("39", date(2022, 9, 12), date(2023, 7, 28)),
+			"goat":    season("goat", date(2020, 1, 7), date(2020, 1, 16)),
+			"36":      season("36", date(2019, 9, 9), date(2020, 6, 12)),
+			"41":      season("41", date(2024, 9, 9), nil),
+			"unknown": season("unknown", nil, nil),
+		}}
+}
+
+func TestSeasonIndexSorted(t *testing.T) {
+	sorted := testDirectory().Seasons.Sorted()
+	expected := []schema.SeasonSlug{"unknown", "36", "goat", "39", "40", "41"}
+	if len(sorted) != len(expected) {
+		t.Fatalf("Sorted() has %d seasons, expected %d", len(sorted), len(expected))
+	}
+	for i, season := range sorted {
+		if season.Slug != expected[i] {
+			t.Errorf("Sorted()[%d] = %s, expected %s", i, season.Slug, expected[i])
+		}
+	}
+}
+
+func TestSeasonFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		date  *schema.ShowDate
+		slug  schema.SeasonSlug
+		found bool
+	}{
+		{"first day", date(2023, 9, 11), "40", true},
+		{"last day", date(2023, 7, 28), "39", true},
+		{"summer break", date(2023, 8, 15), "", false},
+		{"within tournament", date(2020, 1, 9), "goat", true},
+		{"around tournament", date(2020, 1, 17), "36", true},
+		{"open-ended season", date(2025, 6, 1), "41", true},
+		{"before any season", date(1984, 9, 10), "", false},
+	}
+	directory := testDirectory()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			season, found := directory.SeasonFor(*tt.date)
+			if found != tt.found {
+				t.Fatalf("SeasonFor(%s) found = %v, expected %v", tt.date, found, tt.found)
+			}
+			if found && season.Slug != tt.slug {
+				t.Errorf("SeasonFor(%s) = %s, expected %s", tt.date, season.Slug, tt.slug)
+			}
+		})
+	}
+}
+
+func TestSeasonForModified(t *testing.T) {
+	directory := testDirectory()
+	if _, found := directory.SeasonFor(*date(2023, 8, 15)); found {
+		t.Fatal("unexpected season during summer break")
+	}
+	directory.Seasons["summer"] = &schema.SeasonMetadata{
+		SeasonID: schema.SeasonID{Slug: "summer"},
+		Aired:    schema.ShowDateRange{From: date(2023, 8, 1), Until: date(2023, 8, 31)}}
+	if season, found := directory.SeasonFor(*date(2023, 8, 15)); !found || season.Slug != "summer" {
+		t.Errorf("SeasonFor after adding a season = %v, %v", season, found)
+	}
+
+	directory.Seasons["summer"].Aired.Until = date(2023, 8, 10)
+	if season, found := directory.SeasonFor(*date(2023, 8, 15)); found {
+		t.Errorf("SeasonFor after shortening a season = %v", season.Slug)
+	}
+}
+
+func TestSeasonForConcurrent(t *testing.T) {
+	directory := testDirectory()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if season, found := directory.SeasonFor(*date(2023, 9, 11)); !found || season.Slug != "40" {
+				t.Errorf("SeasonFor = %v, %v", season, found)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEpisodeOrdinal(t *testing.T) {
+	episode := func(number int, aired *schema.ShowDate) *schema.MatchMetadata {
+		return &schema.MatchMetadata{
+			MatchID:   schema.MatchID{MatchNumber: schema.MatchNumber(number)},
+			AiredDate: aired}
+	}
+	episodes := schema.MatchIndex{
+		8001: episode(8001, date(2019, 9, 10)),
+		8000: episode(8000, date(2019, 9, 9)),
+		8002: episode(8002, date(2020, 1, 7)),
+		8003: episode(8003, date(2020, 1, 8)),
+		8004: episode(8004, nil),
+		9001: episode(9001, date(2023, 9, 11)),
+	}
+	if _, found := schema.NewMatchCatalog(episodes).EpisodeOrdinal(8000); found {
+		t.Error("ordinal found before the season was assigned")
+	}
+
+	directory := testDirectory()
+	if assigned := directory.AssignSeasons(episodes); assigned != 5 {
+		t.Errorf("AssignSeasons() = %d, expected 5", assigned)
+	}
+	episodes[8004].SeasonSlug = "36"
+	catalog := schema.NewMatchCatalog(episodes)
+
+	tests := []struct {
+		match   schema.MatchNumber
+		ordinal int
+		found   bool
+	}{
+		{8000, 1, true},
+		{8001, 2, true},
+		{8002, 1, true}, // the first match of the "goat" tournament
+		{8003, 2, true},
+		{8004, 3, true}, // without an aired date it is ordered last
+		{9001, 1, true},
+		{9999, 0, false},
+	}
+	for _, tt := range tests {
+		ordinal, found := catalog.EpisodeOrdinal(tt.match)
+		if ordinal != tt.ordinal || found != tt.found {
+			t.Errorf("EpisodeOrdinal(%d) = %d, %v; expected %d, %v",
+				tt.match, ordinal, found, tt.ordinal, tt.found)
+		}
+	}
+}
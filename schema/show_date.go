@@ -23,31 +23,119 @@
 package schema
 
 import (
+	"bytes"
+	"database/sql/driver"
+	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
+//go:embed show_date.cue
+var schemaShowDate string
+
 type ShowDate struct {
 	Year  int `json:"year"`
 	Month int `json:"month"`
 	Day   int `json:"day"`
 }
 
-// formatted as YYYY-MM-DD
-func ParseShowDate(image string) *ShowDate {
-	reShowDate := regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`)
-	matches := reShowDate.FindStringSubmatch(image)
-	// We can ignore the error here because we know these are all digit patterns.
-	year, _ := strconv.Atoi(matches[1])
-	month, _ := strconv.Atoi(matches[2])
-	day, _ := strconv.Atoi(matches[3])
+var (
+	ErrShowDateFormat = errors.New("unrecognized show date format")
+	ErrShowDateRange  = errors.New("show date out of range")
+)
+
+var (
+	// YYYY-MM-DD or YYYY/MM/DD (the separators must match).
+	reShowDateSep = regexp.MustCompile(`\b(\d{4})([-/])(\d{1,2})([-/])(\d{1,2})\b`)
+	// YYYYMMDD
+	reShowDateCompact = regexp.MustCompile(`\b(\d{4})(\d{2})(\d{2})\b`)
+	// [Weekday,] Month DD, YYYY (as in "Monday, March 25, 1995" or "Mar. 25 1995")
+	reShowDateLong = regexp.MustCompile(
+		`(?i)\b([a-z]{3,9})\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+)
+
+var month_names = map[string]int{
+	"january": 1, "february": 2, "march": 3, "april": 4,
+	"may": 5, "june": 6, "july": 7, "august": 8,
+	"september": 9, "october": 10, "november": 11, "december": 12,
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "jun": 6, "jul": 7,
+	"aug": 8, "sep": 9, "sept": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// Parses the first date found in `image`, which may be formatted as any of
+// YYYY-MM-DD, YYYY/MM/DD, YYYYMMDD or "[Weekday,] Month DD, YYYY".  The day is
+// validated against the calendar, including leap years.
+func ParseShowDate(image string) (ShowDate, error) {
+	// We can ignore the Atoi errors here because these are all digit patterns.
+	var year, month, day int
+	if matches := reShowDateSep.FindStringSubmatch(image); matches != nil &&
+		matches[2] == matches[4] {
+		year, _ = strconv.Atoi(matches[1])
+		month, _ = strconv.Atoi(matches[3])
+		day, _ = strconv.Atoi(matches[5])
+	} else if matches := reShowDateCompact.FindStringSubmatch(image); matches != nil {
+		year, _ = strconv.Atoi(matches[1])
+		month, _ = strconv.Atoi(matches[2])
+		day, _ = strconv.Atoi(matches[3])
+	} else if matches := findLongShowDate(image); matches != nil {
+		month = month_names[strings.ToLower(matches[1])]
+		day, _ = strconv.Atoi(matches[2])
+		year, _ = strconv.Atoi(matches[3])
+	} else {
+		return ShowDate{}, fmt.Errorf("%w: %q", ErrShowDateFormat, image)
+	}
+
+	date := ShowDate{Year: year, Month: month, Day: day}
+	if !date.IsValid() {
+		return ShowDate{}, fmt.Errorf("%w: %q", ErrShowDateRange, image)
+	}
+	return date, nil
+}
+
+// Finds the first long-form date whose month name is recognized, so that a
+// preceding word (e.g. "Show #4596 - Monday, ...") is not mistaken for one.
+func findLongShowDate(image string) []string {
+	for _, matches := range reShowDateLong.FindAllStringSubmatch(image, -1) {
+		if _, ok := month_names[strings.ToLower(matches[1])]; ok {
+			return matches
+		}
+	}
+	return nil
+}
+
+// Returns the number of days in the month, or 0 if the month is not valid.
+func DaysInMonth(year, month int) int {
+	switch month {
+	case 1, 3, 5, 7, 8, 10, 12:
+		return 31
+	case 4, 6, 9, 11:
+		return 30
+	case 2:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	}
+	return 0
+}
+
+// True if the month and day are within the calendar for that year.
+func (sd ShowDate) IsValid() bool {
+	return sd.Year > 0 && sd.Day >= 1 && sd.Day <= DaysInMonth(sd.Year, sd.Month)
+}
 
-	return &ShowDate{
-		Year:  year,
-		Month: month,
-		Day:   day,
+// Formatted as YYYY-MM-DD (the String() representation is YYYY/MM/DD).
+func (sd ShowDate) ISODate() string {
+	if sd.Year+sd.Month+sd.Day == 0 {
+		return ""
 	}
+	return fmt.Sprintf("%04d-%02d-%02d", sd.Year, sd.Month, sd.Day)
 }
 
 func (sd ShowDate) String() string {
@@ -57,6 +145,98 @@ func (sd ShowDate) String() string {
 	return fmt.Sprintf("%04d/%02d/%02d", sd.Year, sd.Month, sd.Day)
 }
 
+// Converts the calendar date of t (in t's location) into a ShowDate.
+func FromTime(t time.Time) ShowDate {
+	year, month, day := t.Date()
+	return ShowDate{Year: year, Month: int(month), Day: day}
+}
+
+// Returns midnight (UTC) at the beginning of the show date.
+func (sd ShowDate) Time() time.Time {
+	return time.Date(sd.Year, time.Month(sd.Month), sd.Day, 0, 0, 0, 0, time.UTC)
+}
+
+func (sd ShowDate) Weekday() time.Weekday {
+	return sd.Time().Weekday()
+}
+
+// Implements encoding.TextMarshaler, as YYYY/MM/DD.  This is also the form
+// used when a ShowDate is a map key in JSON.
+func (sd ShowDate) MarshalText() ([]byte, error) {
+	return []byte(sd.String()), nil
+}
+
+// Implements encoding.TextUnmarshaler, accepting any of the formats that
+// ParseShowDate does.  The empty string is the zero value.
+func (sd *ShowDate) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*sd = ShowDate{}
+		return nil
+	}
+	date, err := ParseShowDate(string(text))
+	if err != nil {
+		return err
+	}
+	*sd = date
+	return nil
+}
+
+// Has the same fields as ShowDate but without its (un)marshaling methods.
+type showDateObject ShowDate
+
+// Dates are written as {year, month, day} objects.  Without this, encoding/json
+// would prefer MarshalText and write them as strings.
+func (sd ShowDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(showDateObject(sd))
+}
+
+// Reads either the object form or the compact string form ("YYYY/MM/DD").
+func (sd *ShowDate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var image string
+		if err := json.Unmarshal(data, &image); err != nil {
+			return err
+		}
+		return sd.UnmarshalText([]byte(image))
+	}
+	var object showDateObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*sd = ShowDate(object)
+	return nil
+}
+
+// Implements sql.Scanner for the TEXT columns (aired_date, email_sent, etc.)
+// formatted as YYYY/MM/DD.  NULL is read as the zero value.
+func (sd *ShowDate) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*sd = ShowDate{}
+		return nil
+	case string:
+		return sd.UnmarshalText([]byte(value))
+	case []byte:
+		return sd.UnmarshalText(value)
+	case time.Time:
+		*sd = FromTime(value)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into a ShowDate", src)
+}
+
+// Implements driver.Valuer, writing YYYY/MM/DD or NULL for the zero value.
+func (sd ShowDate) Value() (driver.Value, error) {
+	if sd == (ShowDate{}) {
+		return nil, nil
+	}
+	return sd.String(), nil
+}
+
 // Returns 0 if `this` and `other` are equal;
 // +1 if this is later than other, and -1 if before.
 func (this ShowDate) Compare(other *ShowDate) int {
@@ -84,13 +264,241 @@ func (this ShowDate) Compare(other *ShowDate) int {
 	return 0
 }
 
+// A range of dates, including both endpoints.  Either endpoint may be nil, in
+// which case the range is open-ended (unbounded) on that side.
 type ShowDateRange struct {
 	From  *ShowDate `json:"from,omitempty"`
 	Until *ShowDate `json:"until,omitempty"`
 }
 
+// Convenience function for constructing a range that is closed on both ends.
+func NewShowDateRange(from, until ShowDate) ShowDateRange {
+	return ShowDateRange{From: &from, Until: &until}
+}
+
 func (scope ShowDateRange) Contains(date ShowDate) bool {
-	return (                         // including endpoints,
-	date.Compare(scope.From) >= 0 && // after beginning and
-		date.Compare(scope.Until) <= 0) // before ending
+	// Including endpoints, after beginning and before ending (where defined).
+	return (scope.From == nil || date.Compare(scope.From) >= 0) &&
+		(scope.Until == nil || date.Compare(scope.Until) <= 0)
+}
+
+// True if the range cannot contain any date (its end is before its beginning).
+func (scope ShowDateRange) IsEmpty() bool {
+	return scope.From != nil && scope.Until != nil && scope.From.Compare(scope.Until) > 0
+}
+
+// True if there is at least one date that is in both ranges.
+func (scope ShowDateRange) Overlaps(other ShowDateRange) bool {
+	_, overlaps := scope.Intersect(other)
+	return overlaps
+}
+
+// Returns the dates that are in both ranges, and false if there are none.
+func (scope ShowDateRange) Intersect(other ShowDateRange) (ShowDateRange, bool) {
+	intersection := ShowDateRange{
+		From:  later(scope.From, other.From),
+		Until: earlier(scope.Until, other.Until),
+	}
+	if intersection.IsEmpty() || scope.IsEmpty() || other.IsEmpty() {
+		return ShowDateRange{}, false
+	}
+	return intersection, true
+}
+
+// Returns the covering span of both ranges, the smallest range that includes
+// them.  A range cannot have gaps, so if the ranges are disjoint the span also
+// includes the dates between them.
+func (scope ShowDateRange) Union(other ShowDateRange) ShowDateRange {
+	if scope.IsEmpty() {
+		return other.clone()
+	}
+	if other.IsEmpty() {
+		return scope.clone()
+	}
+	span := ShowDateRange{}
+	if scope.From != nil && other.From != nil {
+		span.From = earlier(scope.From, other.From)
+	}
+	if scope.Until != nil && other.Until != nil {
+		span.Until = later(scope.Until, other.Until)
+	}
+	return span
+}
+
+// Extends the range (if needed) so that it includes date.  An open-ended side
+// of the range remains open, except that extending the zero value (with both
+// sides open) starts a range containing only that date.  This lets a range be
+// accumulated from a sequence of dates, beginning with `var scope ShowDateRange`.
+func (scope *ShowDateRange) Extend(date ShowDate) {
+	if scope.From == nil && scope.Until == nil {
+		*scope = NewShowDateRange(date, date)
+		return
+	}
+	if scope.From != nil && date.Compare(scope.From) < 0 {
+		scope.From = &date
+	}
+	if scope.Until != nil && date.Compare(scope.Until) > 0 {
+		scope.Until = &date
+	}
+}
+
+// Iterates over each date in the range.  The range must have a beginning; if
+// it has no end then iteration continues until the caller stops it.
+func (scope ShowDateRange) Days() iter.Seq[ShowDate] {
+	return func(yield func(ShowDate) bool) {
+		if scope.From == nil || scope.IsEmpty() {
+			return
+		}
+		for day := scope.From.Time(); scope.Until == nil ||
+			!day.After(scope.Until.Time()); day = day.AddDate(0, 0, 1) {
+			if !yield(FromTime(day)) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over each calendar month in the range, clipped to the range.
+func (scope ShowDateRange) Months() iter.Seq[ShowDateRange] {
+	return scope.periods(func(date ShowDate) (ShowDate, ShowDate) {
+		return ShowDate{date.Year, date.Month, 1},
+			ShowDate{date.Year, date.Month, DaysInMonth(date.Year, date.Month)}
+	})
+}
+
+// Iterates over each calendar year in the range, clipped to the range.
+func (scope ShowDateRange) Years() iter.Seq[ShowDateRange] {
+	return scope.periods(func(date ShowDate) (ShowDate, ShowDate) {
+		return ShowDate{date.Year, 1, 1}, ShowDate{date.Year, 12, 31}
+	})
+}
+
+// Yields consecutive periods, each period is determined by `bounds` and the
+// first date of the period.
+func (scope ShowDateRange) periods(bounds func(ShowDate) (ShowDate, ShowDate)) iter.Seq[ShowDateRange] {
+	return func(yield func(ShowDateRange) bool) {
+		if scope.From == nil || scope.IsEmpty() {
+			return
+		}
+		date := *scope.From
+		for scope.Until == nil || date.Compare(scope.Until) <= 0 {
+			first, last := bounds(date)
+			period, _ := NewShowDateRange(first, last).Intersect(scope)
+			if !yield(period) {
+				return
+			}
+			date = FromTime(last.Time().AddDate(0, 0, 1))
+		}
+	}
+}
+
+// Formats the range as a /catwhen fragment, YYYYMMDD-YYYYMMDD, where either
+// side may be empty if the range is open-ended.
+func (scope ShowDateRange) Fragment() string {
+	var from, until string
+	if scope.From != nil {
+		from = fmt.Sprintf("%04d%02d%02d", scope.From.Year, scope.From.Month, scope.From.Day)
+	}
+	if scope.Until != nil {
+		until = fmt.Sprintf("%04d%02d%02d", scope.Until.Year, scope.Until.Month, scope.Until.Day)
+	}
+	return from + "-" + until
+}
+
+var reDateFragment = regexp.MustCompile(`^(\d{4})(\d{2})?(\d{2})?$`)
+
+// Parses the /catwhen fragment syntax, `YYYY[MM[DD]]-YYYY[MM[DD]]`, with an
+// optional leading '#'.  Partial dates include their entire month or year, so
+// "199503-1997" is 1995/03/01 through 1997/12/31.  Either side may be empty for
+// an open-ended range ("2001-" is everything since 2001), and a single value
+// without a '-' is the range of that year, month or day.
+func ParseShowDateRange(fragment string) (ShowDateRange, error) {
+	fragment = strings.TrimPrefix(strings.TrimSpace(fragment), "#")
+	from, until, isRange := strings.Cut(fragment, "-")
+	if !isRange {
+		until = from
+	}
+	if from == "" && until == "" {
+		return ShowDateRange{}, fmt.Errorf("%w: %q", ErrShowDateFormat, fragment)
+	}
+
+	var scope ShowDateRange
+	if from != "" {
+		first, _, err := parseDateFragment(from)
+		if err != nil {
+			return ShowDateRange{}, err
+		}
+		scope.From = &first
+	}
+	if until != "" {
+		_, last, err := parseDateFragment(until)
+		if err != nil {
+			return ShowDateRange{}, err
+		}
+		scope.Until = &last
+	}
+	if scope.IsEmpty() {
+		return ShowDateRange{}, fmt.Errorf("%w: %q ends before it begins", ErrShowDateRange, fragment)
+	}
+	return scope, nil
+}
+
+// Returns the first and last dates that the (possibly partial) date includes.
+func parseDateFragment(image string) (ShowDate, ShowDate, error) {
+	matches := reDateFragment.FindStringSubmatch(image)
+	if matches == nil {
+		return ShowDate{}, ShowDate{}, fmt.Errorf("%w: %q", ErrShowDateFormat, image)
+	}
+	year, _ := strconv.Atoi(matches[1])
+	first, last := ShowDate{year, 1, 1}, ShowDate{year, 12, 31}
+	if matches[2] != "" {
+		month, _ := strconv.Atoi(matches[2])
+		first = ShowDate{year, month, 1}
+		last = ShowDate{year, month, DaysInMonth(year, month)}
+	}
+	if matches[3] != "" {
+		day, _ := strconv.Atoi(matches[3])
+		first.Day, last.Day = day, day
+	}
+	if !first.IsValid() || !last.IsValid() {
+		return ShowDate{}, ShowDate{}, fmt.Errorf("%w: %q", ErrShowDateRange, image)
+	}
+	return first, last, nil
+}
+
+func (scope ShowDateRange) clone() ShowDateRange {
+	var copied ShowDateRange
+	if scope.From != nil {
+		from := *scope.From
+		copied.From = &from
+	}
+	if scope.Until != nil {
+		until := *scope.Until
+		copied.Until = &until
+	}
+	return copied
+}
+
+// Returns (a copy of) the earlier of two range endings, nil being unbounded.
+func earlier(a, b *ShowDate) *ShowDate {
+	if a == nil || (b != nil && b.Compare(a) < 0) {
+		a = b
+	}
+	if a == nil {
+		return nil
+	}
+	date := *a
+	return &date
+}
+
+// Returns (a copy of) the later of two range beginnings, nil being unbounded.
+func later(a, b *ShowDate) *ShowDate {
+	if a == nil || (b != nil && b.Compare(a) > 0) {
+		a = b
+	}
+	if a == nil {
+		return nil
+	}
+	date := *a
+	return &date
 }
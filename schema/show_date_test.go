@@ -24,7 +24,11 @@ package schema_test
 
 import (
 	_ "embed"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kevindamm/q-party/schema"
 )
@@ -103,3 +107,271 @@ func TestDateRangeContains(t *testing.T) {
 		})
 	}
 }
+
+func TestParseShowDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		want    schema.ShowDate
+		wantErr error
+	}{
+		{"dashes", "1995-03-25",
+			schema.ShowDate{Year: 1995, Month: 3, Day: 25}, nil},
+		{"slashes", "1995/03/25",
+			schema.ShowDate{Year: 1995, Month: 3, Day: 25}, nil},
+		{"compact", "19950325",
+			schema.ShowDate{Year: 1995, Month: 3, Day: 25}, nil},
+		{"single-digits", "2004/9/6",
+			schema.ShowDate{Year: 2004, Month: 9, Day: 6}, nil},
+		{"long-form", "March 25, 1995",
+			schema.ShowDate{Year: 1995, Month: 3, Day: 25}, nil},
+		{"abbreviated", "Sept. 6 2004",
+			schema.ShowDate{Year: 2004, Month: 9, Day: 6}, nil},
+		{"archive-title", "Show #4596 - Monday, September 6, 2004",
+			schema.ShowDate{Year: 2004, Month: 9, Day: 6}, nil},
+		{"leap-day", "2000-02-29",
+			schema.ShowDate{Year: 2000, Month: 2, Day: 29}, nil},
+		{"not-leap-day", "1900-02-29",
+			schema.ShowDate{}, schema.ErrShowDateRange},
+		{"april-31", "April 31, 1998",
+			schema.ShowDate{}, schema.ErrShowDateRange},
+		{"month-13", "1998/13/01",
+			schema.ShowDate{}, schema.ErrShowDateRange},
+		{"mixed-separators", "1998-03/01",
+			schema.ShowDate{}, schema.ErrShowDateFormat},
+		{"empty", "",
+			schema.ShowDate{}, schema.ErrShowDateFormat},
+		{"unknown-month", "Smarch 25, 1995",
+			schema.ShowDate{}, schema.ErrShowDateFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schema.ParseShowDate(tt.image)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseShowDate() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseShowDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowDateRoundTrip(t *testing.T) {
+	date := schema.ShowDate{Year: 1984, Month: 9, Day: 10}
+	for _, image := range []string{date.String(), date.ISODate()} {
+		got, err := schema.ParseShowDate(image)
+		if err != nil {
+			t.Fatalf("ParseShowDate(%q) error = %v", image, err)
+		}
+		if got != date {
+			t.Errorf("ParseShowDate(%q) = %v, want %v", image, got, date)
+		}
+	}
+	if date.String() != "1984/09/10" || date.ISODate() != "1984-09-10" {
+		t.Errorf("ShowDate formats = %q, %q", date.String(), date.ISODate())
+	}
+}
+
+func TestShowDateTime(t *testing.T) {
+	date := schema.ShowDate{Year: 1995, Month: 3, Day: 25}
+	if got := date.Weekday(); got != time.Saturday {
+		t.Errorf("ShowDate.Weekday() = %v, want Saturday", got)
+	}
+	local := time.Date(1995, time.March, 25, 23, 30, 0, 0, time.FixedZone("PST", -8*3600))
+	if got := schema.FromTime(local); got != date {
+		t.Errorf("FromTime() = %v, want %v", got, date)
+	}
+	if got := schema.FromTime(date.Time()); got != date {
+		t.Errorf("FromTime(Time()) = %v, want %v", got, date)
+	}
+}
+
+func TestShowDateSQL(t *testing.T) {
+	date := schema.ShowDate{Year: 2004, Month: 9, Day: 6}
+	value, err := date.Value()
+	if err != nil || value != "2004/09/06" {
+		t.Errorf("ShowDate.Value() = %v, %v", value, err)
+	}
+	if value, _ := (schema.ShowDate{}).Value(); value != nil {
+		t.Errorf("ShowDate{}.Value() = %v, want nil", value)
+	}
+
+	tests := []struct {
+		name string
+		src  any
+		want schema.ShowDate
+	}{
+		{"text", "2004/09/06", date},
+		{"bytes", []byte("2004/09/06"), date},
+		{"time", date.Time(), date},
+		{"null", nil, schema.ShowDate{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schema.ShowDate{Year: 1, Month: 1, Day: 1}
+			if err := got.Scan(tt.src); err != nil {
+				t.Fatalf("ShowDate.Scan() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ShowDate.Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	var date2 schema.ShowDate
+	if err := date2.Scan(20040906); err == nil {
+		t.Errorf("ShowDate.Scan(int) expected an error")
+	}
+}
+
+func TestShowDateJSON(t *testing.T) {
+	date := schema.ShowDate{Year: 2004, Month: 9, Day: 6}
+	encoded, err := json.Marshal(date)
+	if err != nil || string(encoded) != `{"year":2004,"month":9,"day":6}` {
+		t.Errorf("json.Marshal(ShowDate) = %s, %v", encoded, err)
+	}
+
+	for _, image := range []string{`{"year":2004,"month":9,"day":6}`, `"2004/09/06"`} {
+		var decoded schema.ShowDate
+		if err := json.Unmarshal([]byte(image), &decoded); err != nil || decoded != date {
+			t.Errorf("json.Unmarshal(%s) = %v, %v", image, decoded, err)
+		}
+	}
+
+	keyed := map[schema.ShowDate]int{date: 4596}
+	encoded, err = json.Marshal(keyed)
+	if err != nil || string(encoded) != `{"2004/09/06":4596}` {
+		t.Errorf("json.Marshal(map) = %s, %v", encoded, err)
+	}
+	decoded := make(map[schema.ShowDate]int)
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded[date] != 4596 {
+		t.Errorf("json.Unmarshal(map) = %v, %v", decoded, err)
+	}
+}
+
+func date(year, month, day int) *schema.ShowDate {
+	return &schema.ShowDate{Year: year, Month: month, Day: day}
+}
+
+func TestDateRangeOpenEnded(t *testing.T) {
+	since := schema.ShowDateRange{From: date(2001, 11, 26)}
+	if !since.Contains(*date(2025, 1, 1)) || since.Contains(*date(2001, 11, 25)) {
+		t.Errorf("open-ended (since) range Contains() is incorrect")
+	}
+	before := schema.ShowDateRange{Until: date(1990, 1, 1)}
+	if !before.Contains(*date(1984, 9, 10)) || before.Contains(*date(1990, 1, 2)) {
+		t.Errorf("open-ended (until) range Contains() is incorrect")
+	}
+	if !(schema.ShowDateRange{}).Contains(*date(1999, 12, 31)) {
+		t.Errorf("unbounded range should contain every date")
+	}
+}
+
+func TestDateRangeIntersectUnion(t *testing.T) {
+	a := schema.NewShowDateRange(*date(1995, 1, 1), *date(1997, 12, 31))
+	b := schema.ShowDateRange{From: date(1997, 6, 1)}
+	c := schema.NewShowDateRange(*date(1999, 1, 1), *date(1999, 12, 31))
+
+	intersection, ok := a.Intersect(b)
+	if !ok || intersection.Fragment() != "19970601-19971231" {
+		t.Errorf("Intersect() = %s, %v", intersection.Fragment(), ok)
+	}
+	if _, ok := a.Intersect(c); ok || a.Overlaps(c) {
+		t.Errorf("disjoint ranges should not intersect")
+	}
+	if !a.Overlaps(b) || !b.Overlaps(c) {
+		t.Errorf("Overlaps() should be true")
+	}
+	if got := a.Union(c).Fragment(); got != "19950101-19991231" {
+		t.Errorf("Union() = %s", got)
+	}
+	if got := a.Union(b).Fragment(); got != "19950101-" {
+		t.Errorf("Union() with open-ended range = %s", got)
+	}
+
+	extended := schema.NewShowDateRange(*date(1995, 3, 25), *date(1995, 3, 25))
+	extended.Extend(*date(1996, 1, 2))
+	extended.Extend(*date(1994, 12, 31))
+	extended.Extend(*date(1995, 7, 4))
+	if got := extended.Fragment(); got != "19941231-19960102" {
+		t.Errorf("Extend() = %s", got)
+	}
+
+	var accumulated schema.ShowDateRange
+	accumulated.Extend(*date(2001, 9, 10))
+	if got := accumulated.Fragment(); got != "20010910-20010910" {
+		t.Errorf("Extend() on the zero range = %s", got)
+	}
+	accumulated.Extend(*date(2001, 9, 3))
+	if got := accumulated.Fragment(); got != "20010903-20010910" {
+		t.Errorf("Extend() after the zero range = %s", got)
+	}
+
+	halfOpen := schema.ShowDateRange{From: date(1997, 6, 1)}
+	halfOpen.Extend(*date(1997, 1, 1))
+	if got := halfOpen.Fragment(); got != "19970101-" {
+		t.Errorf("Extend() with open-ended range = %s", got)
+	}
+}
+
+func TestDateRangeIterators(t *testing.T) {
+	scope := schema.NewShowDateRange(*date(1999, 12, 30), *date(2000, 3, 2))
+	var days []string
+	for day := range scope.Days() {
+		days = append(days, day.String())
+		if len(days) == 4 {
+			break
+		}
+	}
+	if strings.Join(days, " ") != "1999/12/30 1999/12/31 2000/01/01 2000/01/02" {
+		t.Errorf("Days() = %v", days)
+	}
+
+	var months []string
+	for month := range scope.Months() {
+		months = append(months, month.Fragment())
+	}
+	want := []string{"19991230-19991231", "20000101-20000131",
+		"20000201-20000229", "20000301-20000302"}
+	if strings.Join(months, " ") != strings.Join(want, " ") {
+		t.Errorf("Months() = %v, want %v", months, want)
+	}
+
+	var years []string
+	for year := range scope.Years() {
+		years = append(years, year.Fragment())
+	}
+	if strings.Join(years, " ") != "19991230-19991231 20000101-20000302" {
+		t.Errorf("Years() = %v", years)
+	}
+}
+
+func TestParseShowDateRange(t *testing.T) {
+	tests := []struct {
+		fragment string
+		want     string
+		wantErr  error
+	}{
+		{"#19950325-19970602", "19950325-19970602", nil},
+		{"199503-1997", "19950301-19971231", nil},
+		{"2001-", "20010101-", nil},
+		{"-1999", "-19991231", nil},
+		{"200002", "20000201-20000229", nil},
+		{"1984", "19840101-19841231", nil},
+		{"1997-1995", "", schema.ErrShowDateRange},
+		{"199513-1997", "", schema.ErrShowDateRange},
+		{"95-97", "", schema.ErrShowDateFormat},
+		{"-", "", schema.ErrShowDateFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fragment, func(t *testing.T) {
+			got, err := schema.ParseShowDateRange(tt.fragment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseShowDateRange() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Fragment() != tt.want {
+				t.Errorf("ParseShowDateRange() = %s, want %s", got.Fragment(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/validate.go
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	cuejson "cuelang.org/go/encoding/json"
+)
+
+// Identifies which definition (in the embedded .cue schemas) a document is
+// validated against.
+type SchemaKind string
+
+const (
+	KIND_SEASON_DIRECTORY SchemaKind = "#SeasonDirectory"
+	KIND_SEASON           SchemaKind = "#Season"
+	KIND_SEASON_METADATA  SchemaKind = "#SeasonMetadata"
+	KIND_MATCH_METADATA   SchemaKind = "#MatchMetadata"
+	KIND_MATCH_STATS      SchemaKind = "#MatchStats"
+	KIND_MATCH_RECORD     SchemaKind = "#MatchRecord"
+	KIND_CATEGORY         SchemaKind = "#Category"
+	KIND_CHALLENGE        SchemaKind = "#Challenge"
+	KIND_HOST_CHALLENGE   SchemaKind = "#HostChallenge"
+	KIND_PLAYER_WAGER     SchemaKind = "#PlayerWager"
+	KIND_PLAYER_RESPONSE  SchemaKind = "#PlayerResponse"
+	KIND_CONTESTANT       SchemaKind = "#Contestant"
+	KIND_CAREER           SchemaKind = "#Career"
+	KIND_DATA_QUALITY     SchemaKind = "#DataQualityJudgement"
+	KIND_BOARD            SchemaKind = "#Board"
+	KIND_BOARD_STATE      SchemaKind = "#BoardState"
+	KIND_BOARD_POSITION   SchemaKind = "#BoardPosition"
+	KIND_SHOW_DATE        SchemaKind = "#ShowDate"
+	KIND_SHOW_DATE_RANGE  SchemaKind = "#ShowDateRange"
+)
+
+// A single field-level problem found when validating a document.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+func (violation Violation) String() string {
+	if violation.Line > 0 {
+		return fmt.Sprintf("%s (line %d, column %d): %s",
+			violation.Path, violation.Line, violation.Column, violation.Message)
+	}
+	return fmt.Sprintf("%s: %s", violation.Path, violation.Message)
+}
+
+// All of the violations found in a document, returned as the error by Validate.
+type Violations []Violation
+
+func (violations Violations) Error() string {
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.String()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Checks the JSON document against the definition for `kind`.  If the document
+// does not conform, the returned error is of type Violations.
+func Validate(kind SchemaKind, document []byte) error {
+	schema, err := compiledSchema()
+	if err != nil {
+		return err
+	}
+	definition := schema.LookupPath(cue.ParsePath(string(kind)))
+	if !definition.Exists() {
+		return fmt.Errorf("unknown schema kind %q", kind)
+	}
+
+	const filename = "document.json"
+	expr, err := cuejson.Extract(filename, document)
+	if err != nil {
+		return err
+	}
+	value := definition.Unify(schema.Context().BuildExpr(expr))
+	err = value.Validate(cue.Concrete(true), cue.All())
+	if err == nil {
+		return nil
+	}
+
+	// Errors from a failed disjunction are reported once per alternative, these
+	// are collapsed so that there is only one violation for each path.
+	var violations Violations
+	seen := make(map[string]int)
+	for _, cueErr := range cueerrors.Errors(err) {
+		path := jsonPath(kind, cueErr.Path())
+		index, exists := seen[path]
+		if !exists {
+			format, args := cueErr.Msg()
+			index = len(violations)
+			seen[path] = index
+			violations = append(violations, Violation{
+				Path:    path,
+				Message: fmt.Sprintf(format, args...)})
+		}
+		violation := &violations[index]
+		if violation.Line > 0 {
+			continue
+		}
+		for _, pos := range cueerrors.Positions(cueErr) {
+			if pos.Filename() == filename {
+				violation.Line, violation.Column = pos.Line(), pos.Column()
+				break
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return err
+	}
+	return violations
+}
+
+// Formats a CUE path (relative to the definition) in the same style as the
+// path in a DecodeError, e.g. "$.episodes.1.contestants[0].cid".
+func jsonPath(kind SchemaKind, path []string) string {
+	if len(path) > 0 && path[0] == string(kind) {
+		path = path[1:]
+	}
+	var builder strings.Builder
+	builder.WriteString("$")
+	for _, selector := range path {
+		if _, err := strconv.Atoi(selector); err == nil {
+			builder.WriteString("[" + selector + "]")
+			continue
+		}
+		if unquoted, err := strconv.Unquote(selector); err == nil {
+			selector = unquoted
+		}
+		builder.WriteString("." + selector)
+	}
+	return builder.String()
+}
+
+var compiledSchema = sync.OnceValues(func() (cue.Value, error) {
+	sources := map[string]string{
+		"category.cue":     schemaCategories,
+		"challenge.cue":    schemaChallenge,
+		"contestant.cue":   schemaContestants,
+		"data_quality.cue": schemaDataQuality,
+		"match.cue":        schemaMatches,
+		"record.cue":       schemaRecords,
+		"round.cue":        schemaRounds,
+		"season.cue":       schemaSeasons,
+		"show_date.cue":    schemaShowDate,
+	}
+	instance := build.NewContext().NewInstance("schema", nil)
+	for filename, source := range sources {
+		if err := instance.AddFile(filename, source); err != nil {
+			return cue.Value{}, err
+		}
+	}
+	value := cuecontext.New().BuildInstance(instance)
+	if err := value.Err(); err != nil {
+		return cue.Value{}, errors.Join(
+			errors.New("embedded schema does not compile"), err)
+	}
+	return value, nil
+})
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/value.go
+
+package schema
+
+import "sort"
+
+// The nominal values of each board position, which have changed over the years.
+// Each row is worth its index times the era's base value, and twice that in the
+// Double round.  Rounds without board values (e.g. Final, which is wagered) are
+// not on the ladder.
+type ValueLadder struct {
+	Eras []ValueEra // ordered by when they began
+}
+
+type ValueEra struct {
+	Since ShowDate
+	Base  Value
+}
+
+// Values doubled from 100-500 to 200-1000 (in Single) on November 26, 2001.
+var ClassicValues = ValueLadder{Eras: []ValueEra{
+	{Since: ShowDate{Year: 1984, Month: 9, Day: 10}, Base: 100},
+	{Since: ShowDate{Year: 2001, Month: 11, Day: 26}, Base: 200},
+}}
+
+// The era in effect for a match aired on this date.  Dates before the first era
+// are considered part of it.
+func (ladder ValueLadder) era(aired ShowDate) ValueEra {
+	if len(ladder.Eras) == 0 {
+		return ValueEra{}
+	}
+	i := sort.Search(len(ladder.Eras), func(i int) bool {
+		return ladder.Eras[i].Since.Compare(&aired) > 0
+	})
+	return ladder.Eras[max(i-1, 0)]
+}
+
+func roundMultiplier(round RoundEnum) Value {
+	switch round {
+	case ROUND_SINGLE:
+		return 1
+	case ROUND_DOUBLE:
+		return 2
+	}
+	return 0
+}
+
+// The nominal value for the row (1 at the top) in the round, as aired on this
+// date.  Zero if the round has no board values.
+func (ladder ValueLadder) Value(round RoundEnum, index uint, aired ShowDate) Value {
+	return ladder.era(aired).Base * roundMultiplier(round) * Value(index)
+}
+
+// The row (1 at the top) that has this nominal value in the round, as aired on
+// this date.  False if none of the round's rows have that value.
+func (ladder ValueLadder) Index(round RoundEnum, value Value, aired ShowDate) (uint, bool) {
+	step := ladder.era(aired).Base * roundMultiplier(round)
+	if step <= 0 || value <= 0 || value%step != 0 ||
+		value/step > Value(round.Rules().Rows) {
+		return 0, false
+	}
+	return uint(value / step), true
+}
+
+// Scales a value (or wager, or score) from the era it aired in to the latest.
+func (ladder ValueLadder) Normalize(value Value, aired ShowDate) Value {
+	if len(ladder.Eras) == 0 {
+		return value
+	}
+	base := ladder.era(aired).Base
+	if base == 0 {
+		return value
+	}
+	return value * ladder.Eras[len(ladder.Eras)-1].Base / base
+}
+
+// The value of a position in the latest era, for comparing across eras.
+func (ladder ValueLadder) Modern(round RoundEnum, index uint) Value {
+	if len(ladder.Eras) == 0 {
+		return 0
+	}
+	return ladder.Eras[len(ladder.Eras)-1].Base * roundMultiplier(round) * Value(index)
+}
+
+// The nominal value of a position as aired on this date, or in the latest era
+// when the date is not known.
+func (ladder ValueLadder) valueAired(round RoundEnum, index uint, aired *ShowDate) Value {
+	if aired == nil {
+		return ladder.Modern(round, index)
+	}
+	return ladder.Value(round, index, *aired)
+}
+
+// Sets the nominal value of each of the round's challenges that has none, as
+// when importing boards where special challenges only show their wager.
+// Returns the number of challenges that were given a value.
+func (ladder ValueLadder) FillValues(round *RoundRecord, aired ShowDate) int {
+	filled := 0
+	for i := range round.Challenges {
+		challenge := &round.Challenges[i]
+		if challenge.Value != 0 {
+			continue
+		}
+		if value := ladder.Value(round.Round, challenge.Index, aired); value != 0 {
+			challenge.Value = value
+			filled++
+		}
+	}
+	return filled
+}
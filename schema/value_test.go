@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/value_test.go
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func TestValueLadder(t *testing.T) {
+	ladder := schema.ClassicValues
+	tests := []struct {
+		name  string
+		round schema.RoundEnum
+		index uint
+		aired *schema.ShowDate
+		value schema.Value
+	}{
+		{"first single", schema.ROUND_SINGLE, 1, date(1984, 9, 10), 100},
+		{"last before doubling", schema.ROUND_DOUBLE, 5, date(2001, 11, 23), 1000},
+		{"first after doubling", schema.ROUND_DOUBLE, 5, date(2001, 11, 26), 2000},
+		{"modern single", schema.ROUND_SINGLE, 3, date(2024, 1, 2), 600},
+		{"before the first era", schema.ROUND_SINGLE, 2, date(1980, 1, 1), 200},
+		{"final", schema.ROUND_FINAL, 1, date(2024, 1, 2), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := ladder.Value(tt.round, tt.index, *tt.aired)
+			if value != tt.value {
+				t.Errorf("Value() = %d, expected %d", value, tt.value)
+			}
+			index, found := ladder.Index(tt.round, tt.value, *tt.aired)
+			if found != (tt.value != 0) || (found && index != tt.index) {
+				t.Errorf("Index(%d) = %d, %v", tt.value, index, found)
+			}
+		})
+	}
+
+	if _, found := ladder.Index(schema.ROUND_SINGLE, 300, *date(2010, 1, 1)); found {
+		t.Error("found an index for 300 in the modern single round")
+	}
+	if index, found := ladder.Index(schema.ROUND_SINGLE, 5000, *date(2010, 1, 1)); found {
+		t.Errorf("found index %d for 5000 in the modern single round", index)
+	}
+	if value := ladder.Normalize(800, *date(1999, 1, 1)); value != 1600 {
+		t.Errorf("Normalize(800, 1999) = %d", value)
+	}
+	if value := ladder.Normalize(800, *date(2019, 1, 1)); value != 800 {
+		t.Errorf("Normalize(800, 2019) = %d", value)
+	}
+	if value := ladder.Modern(schema.ROUND_DOUBLE, 4); value != 1600 {
+		t.Errorf("Modern(double, 4) = %d", value)
+	}
+}
+
+func TestValueLadderFillValues(t *testing.T) {
+	round := fullRound(schema.ROUND_DOUBLE, schema.BoardPosition{Column: 2, Index: 3})
+	for i := range round.Challenges {
+		if !round.Challenges[i].Special {
+			round.Challenges[i].Value = 1
+		}
+	}
+	if filled := schema.ClassicValues.FillValues(round, *date(1995, 3, 1)); filled != 1 {
+		t.Errorf("FillValues() = %d, expected 1", filled)
+	}
+	if challenge, _ := round.ChallengeAt(schema.BoardPosition{Column: 2, Index: 3}); challenge.Value != 600 {
+		t.Errorf("filled value = %d, expected 600", challenge.Value)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/wager.go
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Decides which wagers a contestant may make, given the scores at the time.
+type WagerPolicy interface {
+	// The range of wagers allowed, or an error (a WagerError) if the contestant
+	// may not wager at all.
+	Limits(contestant ContestantID, snapshot ScoreSnapshot) (min, max Wager, err error)
+	// Returns a WagerError if the wager is not allowed.
+	Validate(wager PlayerWager, snapshot ScoreSnapshot) error
+}
+
+// The scores at the time of a wager, and the round it is made in.
+type ScoreSnapshot struct {
+	Round  RoundEnum
+	Scores map[ContestantKey]Value
+	// The highest value on the round's board, or zero for the ClassicValues one
+	// in the era of the aired date (the latest era if it is not known).
+	TopValue Value
+	Aired    *ShowDate
+}
+
+// Returns each contestant's latest score, as of the end of the match so far.
+func (record *MatchRecord) Snapshot(round RoundEnum) ScoreSnapshot {
+	snapshot := ScoreSnapshot{
+		Round:  round,
+		Scores: make(map[ContestantKey]Value),
+		Aired:  record.AiredDate}
+	for _, timeline := range record.ReplayScores() {
+		var score Value
+		if len(timeline.Scores) > 0 {
+			score = timeline.Scores[len(timeline.Scores)-1]
+		}
+		snapshot.Scores[timeline.Key()] = score
+	}
+	return snapshot
+}
+
+func (snapshot ScoreSnapshot) topValue() Value {
+	if snapshot.TopValue != 0 {
+		return snapshot.TopValue
+	}
+	return ClassicValues.valueAired(snapshot.Round, snapshot.Round.Rules().Rows, snapshot.Aired)
+}
+
+var (
+	ErrWagerTooLow       = errors.New("wager is less than the minimum")
+	ErrWagerTooHigh      = errors.New("wager is more than the maximum")
+	ErrWagerExcluded     = errors.New("contestant may not wager")
+	ErrWagerRound        = errors.New("round does not have wagers")
+	ErrUnknownContestant = errors.New("contestant is not in the match")
+)
+
+// The reason a wager was not allowed, along with the allowed range (if any) so
+// that it can be shown to the contestant.
+type WagerError struct {
+	Contestant ContestantID
+	Wager      Wager
+	Min, Max   Wager
+	Err        error
+}
+
+func (err WagerError) Error() string {
+	switch err.Err {
+	case ErrWagerTooLow, ErrWagerTooHigh:
+		return fmt.Sprintf("%s: %d is not between %d and %d",
+			err.Err, err.Wager, err.Min, err.Max)
+	}
+	return err.Err.Error()
+}
+
+func (err WagerError) Unwrap() error {
+	return err.Err
+}
+
+// The classic rules: a daily double may be up to the contestant's score or the
+// round's top value, whichever is more (and at least 5).  In Final, wagers may
+// be up to the contestant's score, which must be positive to play.
+type ClassicWagers struct{}
+
+var classicHouseRules = HouseRules{MinSpecial: 5}
+
+func (ClassicWagers) Limits(contestant ContestantID, snapshot ScoreSnapshot) (Wager, Wager, error) {
+	return classicHouseRules.Limits(contestant, snapshot)
+}
+
+func (ClassicWagers) Validate(wager PlayerWager, snapshot ScoreSnapshot) error {
+	return classicHouseRules.Validate(wager, snapshot)
+}
+
+// Variations on the classic rules, the zero value is the same as the classic
+// rules except for allowing a daily double wager of zero.
+type HouseRules struct {
+	// The least that may be wagered on a daily double.
+	MinSpecial Wager
+	// If non-zero, daily doubles may be wagered up to this instead of the round's
+	// top value (when it is more than the contestant's score).
+	SpecialCap Value
+	// If non-zero, contestants without a positive score may still play Final and
+	// wager up to this amount.
+	FinalFloor Wager
+}
+
+func (rules HouseRules) Limits(contestant ContestantID, snapshot ScoreSnapshot) (Wager, Wager, error) {
+	score, found := snapshot.Scores[contestant.Key()]
+	if !found {
+		return 0, 0, WagerError{Contestant: contestant, Err: ErrUnknownContestant}
+	}
+	switch snapshot.Round.Rules().Wagers {
+	case WAGER_SPECIALS:
+		top := snapshot.topValue()
+		if rules.SpecialCap != 0 {
+			top = rules.SpecialCap
+		}
+		return rules.MinSpecial, Wager(max(score, top, Value(rules.MinSpecial))), nil
+	case WAGER_ALL:
+		if score > 0 {
+			return 0, Wager(score), nil
+		}
+		if rules.FinalFloor > 0 {
+			return 0, rules.FinalFloor, nil
+		}
+		return 0, 0, WagerError{Contestant: contestant, Err: ErrWagerExcluded}
+	}
+	return 0, 0, WagerError{Contestant: contestant, Err: ErrWagerRound}
+}
+
+func (rules HouseRules) Validate(wager PlayerWager, snapshot ScoreSnapshot) error {
+	least, most, err := rules.Limits(wager.ContestantID, snapshot)
+	if err != nil {
+		return err
+	}
+	switch {
+	case wager.Wager < least:
+		err = ErrWagerTooLow
+	case wager.Wager > most:
+		err = ErrWagerTooHigh
+	default:
+		return nil
+	}
+	return WagerError{wager.ContestantID, wager.Wager, least, most, err}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Kevin Damm
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+//
+// github:kevindamm/q-party/schema/wager_test.go
+
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func TestWagerPolicies(t *testing.T) {
+	scores := map[schema.ContestantKey]schema.Value{{PK: 1}: 3000, {PK: 2}: 400, {PK: 3}: -200}
+	snapshot := func(round schema.RoundEnum) schema.ScoreSnapshot {
+		return schema.ScoreSnapshot{Round: round, Scores: scores}
+	}
+	house := schema.HouseRules{MinSpecial: 100, SpecialCap: 5000, FinalFloor: 1000}
+
+	tests := []struct {
+		name       string
+		policy     schema.WagerPolicy
+		round      schema.RoundEnum
+		contestant uint64
+		wager      schema.Wager
+		err        error
+	}{
+		{"up to score", schema.ClassicWagers{}, schema.ROUND_SINGLE, 1, 3000, nil},
+		{"over score", schema.ClassicWagers{}, schema.ROUND_SINGLE, 1, 3001, schema.ErrWagerTooHigh},
+		{"up to top value", schema.ClassicWagers{}, schema.ROUND_SINGLE, 2, 1000, nil},
+		{"over top value", schema.ClassicWagers{}, schema.ROUND_SINGLE, 2, 1001, schema.ErrWagerTooHigh},
+		{"double top value", schema.ClassicWagers{}, schema.ROUND_DOUBLE, 3, 2000, nil},
+		{"under minimum", schema.ClassicWagers{}, schema.ROUND_DOUBLE, 3, 4, schema.ErrWagerTooLow},
+		{"final everything", schema.ClassicWagers{}, schema.ROUND_FINAL, 2, 400, nil},
+		{"final nothing", schema.ClassicWagers{}, schema.ROUND_FINAL, 2, 0, nil},
+		{"final over score", schema.ClassicWagers{}, schema.ROUND_FINAL, 2, 401, schema.ErrWagerTooHigh},
+		{"final excluded", schema.ClassicWagers{}, schema.ROUND_FINAL, 3, 0, schema.ErrWagerExcluded},
+		{"tiebreaker", schema.ClassicWagers{}, schema.ROUND_TIEBREAKER, 1, 100, schema.ErrWagerRound},
+		{"unknown contestant", schema.ClassicWagers{}, schema.ROUND_FINAL, 4, 100, schema.ErrUnknownContestant},
+		{"house cap", house, schema.ROUND_SINGLE, 2, 5000, nil},
+		{"house minimum", house, schema.ROUND_SINGLE, 2, 50, schema.ErrWagerTooLow},
+		{"house floor", house, schema.ROUND_FINAL, 3, 1000, nil},
+		{"over house floor", house, schema.ROUND_FINAL, 3, 1001, schema.ErrWagerTooHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wager := schema.PlayerWager{
+				ContestantID: schema.ContestantID{PK: tt.contestant},
+				Wager:        tt.wager}
+			err := tt.policy.Validate(wager, snapshot(tt.round))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Validate() error = %v, expected %v", err, tt.err)
+			}
+			var wagerErr schema.WagerError
+			if err != nil && (!errors.As(err, &wagerErr) || wagerErr.Contestant.PK != tt.contestant) {
+				t.Errorf("error %v is not a WagerError for the contestant", err)
+			}
+		})
+	}
+}
+
+func TestWagerEraTopValue(t *testing.T) {
+	snapshot := schema.ScoreSnapshot{
+		Round:  schema.ROUND_SINGLE,
+		Scores: map[schema.ContestantKey]schema.Value{{PK: 1}: 200},
+		Aired:  date(1995, 3, 14)}
+	_, most, err := schema.ClassicWagers{}.Limits(schema.ContestantID{PK: 1}, snapshot)
+	if err != nil || most != 500 {
+		t.Errorf("Limits() before 2001 = %d, %v; expected 500", most, err)
+	}
+}
+
+func TestWagerErrorLimits(t *testing.T) {
+	snapshot := schema.ScoreSnapshot{
+		Round: schema.ROUND_DOUBLE, Scores: map[schema.ContestantKey]schema.Value{{PK: 1}: 4400}, TopValue: 1000}
+	err := schema.ClassicWagers{}.Validate(schema.PlayerWager{
+		ContestantID: schema.ContestantID{PK: 1, Name: "Ada"}, Wager: 5000}, snapshot)
+
+	var wagerErr schema.WagerError
+	if !errors.As(err, &wagerErr) || wagerErr.Min != 5 || wagerErr.Max != 4400 {
+		t.Fatalf("Validate() error = %#v", err)
+	}
+	if message := err.Error(); message != "wager is more than the maximum: 5000 is not between 5 and 4400" {
+		t.Errorf("Error() = %q", message)
+	}
+}
+
+func TestMatchRecordSnapshot(t *testing.T) {
+	snapshot := testRecord().Snapshot(schema.ROUND_FINAL)
+	if snapshot.Scores[schema.ContestantKey{PK: 1}] != -700 ||
+		snapshot.Scores[schema.ContestantKey{PK: 2}] != -1 {
+		t.Errorf("Snapshot() scores = %v", snapshot.Scores)
+	}
+	if snapshot.Aired == nil || *snapshot.Aired != *date(2019, 9, 9) {
+		t.Errorf("Snapshot() aired = %v", snapshot.Aired)
+	}
+	if _, _, err := (schema.ClassicWagers{}).Limits(schema.ContestantID{PK: 1}, snapshot); !errors.Is(err, schema.ErrWagerExcluded) {
+		t.Errorf("Limits() error = %v", err)
+	}
+}
+
+func TestMatchRecordSnapshotByName(t *testing.T) {
+	ada := schema.ContestantID{Name: "Ada"}
+	bo := schema.ContestantID{Name: "Bo"}
+	record := &schema.MatchRecord{
+		MatchMetadata: schema.MatchMetadata{Contestants: []schema.ContestantID{ada, bo}},
+		Rounds: []schema.RoundRecord{{History: []schema.RecordedOutcome{
+			{ContestantID: bo, SelectionOutcome: schema.SelectionOutcome{Correct: true, Delta: 400}},
+			{ContestantID: ada, SelectionOutcome: schema.SelectionOutcome{Correct: true, Delta: 200}},
+		}}},
+	}
+	snapshot := record.Snapshot(schema.ROUND_DOUBLE)
+	if snapshot.Scores[ada.Key()] != 200 || snapshot.Scores[bo.Key()] != 400 {
+		t.Errorf("Snapshot() scores = %v", snapshot.Scores)
+	}
+}
@@ -21,3 +21,98 @@
 // github:kevindamm/q-party/schema/writer.go
 
 package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// The Write* functions emit canonical JSON: two-space indentation, a trailing
+// newline, no HTML escaping and sorted map keys (numerically sorted for the
+// MatchIndex, lexically for the SeasonIndex and CategoryIndex).  Writing the
+// same document twice will produce byte-identical output.
+
+// Writes the season directory document (its version and index of seasons).
+func WriteSeasonDirectory(writer io.Writer, directory *SeasonDirectory) error {
+	return writeCanonical(writer, directory)
+}
+
+// Writes a single season's document, including its episodes and categories.
+func WriteSeason(writer io.Writer, season *Season) error {
+	return writeCanonical(writer, season)
+}
+
+// Writes the metadata for a single match (episode).
+func WriteMatch(writer io.Writer, match *MatchMetadata) error {
+	return writeCanonical(writer, match)
+}
+
+// Writes the complete transcript of a match.
+func WriteMatchRecord(writer io.Writer, record *MatchRecord) error {
+	return writeCanonical(writer, record)
+}
+
+// Writes a category and its challenges.
+func WriteCategory(writer io.Writer, category *Category) error {
+	return writeCanonical(writer, category)
+}
+
+// Writes to a temporary file in the same directory as path and then renames it
+// into place, so that readers never observe a partially written file.  A file
+// being replaced keeps its permissions, new files are created with 0644.
+//
+//	err := schema.WriteFileAtomic(path, func(writer io.Writer) error {
+//	  return schema.WriteSeason(writer, season)
+//	})
+func WriteFileAtomic(path string, write func(io.Writer) error) (err error) {
+	tmpfile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmpfile.Close()
+			os.Remove(tmpfile.Name())
+		}
+	}()
+
+	if err = write(tmpfile); err != nil {
+		return err
+	}
+	if err = tmpfile.Sync(); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if existing, statErr := os.Stat(path); statErr == nil {
+		mode = existing.Mode().Perm()
+	}
+	if err = tmpfile.Chmod(mode); err != nil {
+		return err
+	}
+	if err = tmpfile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpfile.Name(), path)
+}
+
+func writeCanonical(writer io.Writer, document any) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// Same as json.Marshal but without escaping HTML characters (&, <, >) which
+// are common in clues and comments.
+func marshalCompact(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
+}
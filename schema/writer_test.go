@@ -21,3 +21,139 @@
 // github:kevindamm/q-party/schema/writer_test.go
 
 package schema_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevindamm/q-party/schema"
+)
+
+func testSeason() *schema.Season {
+	season := &schema.Season{
+		SeasonMetadata: schema.SeasonMetadata{
+			SeasonID: schema.SeasonID{Slug: "s01", Title: "Season 1"},
+			Aired: schema.ShowDateRange{
+				From:  &schema.ShowDate{Year: 1984, Month: 9, Day: 10},
+				Until: &schema.ShowDate{Year: 1985, Month: 6, Day: 7}},
+		},
+		Episodes:   make(schema.MatchIndex),
+		Categories: make(schema.CategoryIndex),
+	}
+	for _, match := range []int{10, 9, 100, 1} {
+		season.Episodes.Update(schema.MatchMetadata{
+			MatchID:  schema.NewMatchID(match),
+			Comments: "Q&A <round>"})
+	}
+	for _, name := range []schema.CategoryName{"ZOOLOGY", "ART", "MUSIC"} {
+		season.Categories[name] = []schema.CategoryAired{{
+			CategoryMetadata: schema.CategoryMetadata{Name: name},
+			Aired:            schema.ShowDate{Year: 1984, Month: 9, Day: 10}}}
+	}
+	return season
+}
+
+func TestWriteSeasonCanonical(t *testing.T) {
+	var first, second bytes.Buffer
+	if err := schema.WriteSeason(&first, testSeason()); err != nil {
+		t.Fatalf("WriteSeason() error = %v", err)
+	}
+	if err := schema.WriteSeason(&second, testSeason()); err != nil {
+		t.Fatalf("WriteSeason() error = %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("WriteSeason() is not deterministic")
+	}
+
+	output := first.String()
+	if !strings.HasSuffix(output, "}\n") {
+		t.Errorf("WriteSeason() missing trailing newline")
+	}
+	if !strings.Contains(output, "Q&A <round>") {
+		t.Errorf("WriteSeason() should not escape HTML characters")
+	}
+	if !strings.Contains(output, "\n  \"episodes\": {\n    \"1\": {") {
+		t.Errorf("WriteSeason() unexpected indentation:\n%s", output)
+	}
+	order := []string{`"1":`, `"9":`, `"10":`, `"100":`, `"ART"`, `"MUSIC"`, `"ZOOLOGY"`}
+	last := -1
+	for _, key := range order {
+		index := strings.Index(output, key)
+		if index <= last {
+			t.Errorf("WriteSeason() key %s out of order", key)
+		}
+		last = index
+	}
+}
+
+func TestWriteSeasonRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := schema.WriteSeason(&buffer, testSeason()); err != nil {
+		t.Fatalf("WriteSeason() error = %v", err)
+	}
+	written := buffer.String()
+	season, err := schema.LoadSeason(&buffer, schema.Strict())
+	if err != nil {
+		t.Fatalf("LoadSeason() error = %v", err)
+	}
+	var rewritten bytes.Buffer
+	if err := schema.WriteSeason(&rewritten, season); err != nil {
+		t.Fatalf("WriteSeason() error = %v", err)
+	}
+	if rewritten.String() != written {
+		t.Errorf("round trip differs:\n%s\n---\n%s", written, rewritten.String())
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "4596.json")
+	match := &schema.MatchMetadata{MatchID: schema.NewMatchID(4596)}
+	err := schema.WriteFileAtomic(path, func(writer io.Writer) error {
+		return schema.WriteMatch(writer, match)
+	})
+	if err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(contents) != "{\n  \"match\": 4596\n}\n" {
+		t.Errorf("WriteFileAtomic() wrote %q", contents)
+	}
+	if stat, _ := os.Stat(path); stat.Mode().Perm() != 0644 {
+		t.Errorf("WriteFileAtomic() created mode %v, want 0644", stat.Mode().Perm())
+	}
+
+	// Replacing a file keeps its permissions.
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = schema.WriteFileAtomic(path, func(writer io.Writer) error {
+		return schema.WriteMatch(writer, match)
+	})
+	if err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	if stat, _ := os.Stat(path); stat.Mode().Perm() != 0600 {
+		t.Errorf("WriteFileAtomic() replaced mode 0600 with %v", stat.Mode().Perm())
+	}
+
+	// A failed write leaves neither the destination nor a temporary file.
+	failed := filepath.Join(dir, "failed.json")
+	err = schema.WriteFileAtomic(failed, func(writer io.Writer) error {
+		return os.ErrInvalid
+	})
+	if err != os.ErrInvalid {
+		t.Errorf("WriteFileAtomic() error = %v, want %v", err, os.ErrInvalid)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("WriteFileAtomic() left %d files, want 1", len(entries))
+	}
+}